@@ -4,7 +4,9 @@ package main
 import (
 	"context"
 	"database/sql"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -14,7 +16,11 @@ import (
 
 	"admin-server/internal/api"
 	"admin-server/internal/api/config"
+	"admin-server/internal/auth"
 	"admin-server/internal/database"
+	"admin-server/internal/logging"
+	"admin-server/internal/secrets"
+	"admin-server/internal/settings"
 	"admin-server/internal/util"
 	"admin-server/internal/worker"
 
@@ -22,33 +28,65 @@ import (
 )
 
 func main() {
-	cfg, err := config.Load()
+	cfg, err := config.Load(os.Args[1:])
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 	// logger := log.New(os.Stdout, "", log.LstdFlags)
-	fileLogger, err :=  util.NewFileLogger("./logs")
+	fileLogger, err := util.NewFileLogger(cfg.FileLoggerOptions())
 	if err != nil {
 		log.Fatalf("Failed to create file logger: %v", err)
 	}
-	logger := fileLogger.Logger
 	defer fileLogger.Close()
+	// JSON lines go to stdout and the log file; slog.SetDefault also routes the standard log package through it
+	logger := logging.New(io.MultiWriter(os.Stdout, fileLogger), cfg.LogLevel())
+	slog.SetDefault(logger)
 
 
 	// Initialize database connection
-	db, err := sql.Open("postgres", cfg.DatabaseURL)
+	db, err := sql.Open("postgres", cfg.Database.URL)
 	if err != nil {
-		logger.Fatal(err)
+		logger.Error("open database failed", "error", err)
+		os.Exit(1)
 	}
 	defer db.Close()
 
 	// Create queries
 	queries := database.New(db)
 
+	// Create the first admin user from ADMIN_EMAIL and ADMIN_PASSWORD on a fresh database
+	if created, err := auth.EnsureAdmin(context.Background(), queries, cfg.Auth.AdminEmail, cfg.Auth.AdminPassword); err != nil {
+		logger.Error("bootstrap admin user failed", "error", err)
+		os.Exit(1)
+	} else if created {
+		logger.Info("created admin user", "email", cfg.Auth.AdminEmail)
+	}
+
 	// Create context that listens for interrupt signals
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Credentials and upstream URLs come from the database, mounted files and the
+	// environment, in that order of precedence, and are reloaded in the background
+	var providers []secrets.Provider
+	if key := cfg.SecretsKey(); key != nil {
+		dbSecrets, err := secrets.NewDBProvider(queries, key)
+		if err != nil {
+			logger.Error("create database secrets backend failed", "error", err)
+			os.Exit(1)
+		}
+		providers = append(providers, dbSecrets)
+	}
+	if cfg.Secrets.Dir != "" {
+		providers = append(providers, secrets.NewFileProvider(cfg.Secrets.Dir))
+	}
+	providers = append(providers, secrets.NewEnvProvider(secrets.Known...))
+	secretStore := secrets.NewStore(logger.With("component", "secrets"), providers...)
+	if err := secretStore.Reload(ctx); err != nil {
+		logger.Warn("load secrets failed", "error", err)
+	}
+	go secretStore.Watch(ctx, time.Duration(cfg.Secrets.ReloadInterval))
+
 	// Handle OS signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -56,22 +94,45 @@ func main() {
 	// WaitGroup to track all services
 	var wg sync.WaitGroup
 
+	// Runtime settings start from the configuration and are overridden by the
+	// settings table; the reload picks up changes made by other instances
+	settingsStore := settings.NewStore(db, settings.Settings{
+		PollInterval:     time.Duration(cfg.Worker.PollInterval),
+		PoolSize:         cfg.Worker.PoolSize,
+		StaleDays:        cfg.Worker.StaleDays,
+		ScanInterval:     time.Duration(cfg.Worker.ScanInterval),
+		DataModels:       worker.DefaultMapping.Sources(),
+		NoscopeRateLimit: cfg.Worker.NoscopeRateLimit,
+		MuninnRateLimit:  cfg.Worker.MuninnRateLimit,
+		Processor:        settings.ProcessorState{State: settings.StateRunning},
+		Scheduler:        settings.StateRunning,
+	}, func(s settings.Settings) error {
+		return worker.DefaultMapping.CheckSources(s.DataModels)
+	}, logger.With("component", "settings"))
+	if err := settingsStore.Load(ctx); err != nil {
+		logger.Warn("load settings failed, using configured values", "error", err)
+	}
+	go settingsStore.Watch(ctx, time.Duration(cfg.Worker.SettingsReloadInterval))
+
 	// Start worker manager
-	mgr := worker.NewManager(db,fileLogger.Logger)
+	mgr := worker.NewManager(db, secretStore, settingsStore, worker.Options{
+		WorkerID:  cfg.Worker.ID,
+		MaxTags:   cfg.Worker.MaxTags,
+		Processor: cfg.RunsProcessor(),
+		Scheduler: cfg.RunsScheduler(),
+	}, logger.With("component", "worker"))
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		if err := mgr.Run(ctx); err != nil {
-			logger.Printf("Worker manager error: %v", err)
-		}
+		mgr.Run(ctx, time.Duration(cfg.Worker.DrainTimeout))
 	}()
 
 	// Initialize router
-	router := api.NewRouter(queries, logger, db, mgr)
+	router := api.NewRouter(queries, logger, db, mgr, secretStore, settingsStore, cfg)
 
 	// Create server
 	server := &http.Server{
-		Addr:    ":8181",
+		Addr:    cfg.HTTP.Addr,
 		Handler: router,
 	}
 
@@ -79,18 +140,18 @@ func main() {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		logger.Printf("Server starting on :8181, start writing to file")
+		logger.Info("server starting", "addr", server.Addr, "mode", cfg.Mode)
 		if err := server.ListenAndServe(); err != http.ErrServerClosed {
-			logger.Printf("Server error: %v", err)
+			logger.Error("server failed", "error", err)
 		}
 	}()
 
 	// Wait for shutdown signal
 	<-sigChan
-	logger.Println("Received shutdown signal")
+	logger.Info("received shutdown signal")
 
 	// Create shutdown context with timeout
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), time.Duration(cfg.HTTP.ShutdownTimeout))
 	defer shutdownCancel()
 
 	// Cancel main context to stop worker
@@ -98,10 +159,10 @@ func main() {
 
 	// Shutdown server gracefully
 	if err := server.Shutdown(shutdownCtx); err != nil {
-		logger.Printf("Server shutdown error: %v", err)
+		logger.Error("server shutdown failed", "error", err)
 	}
 
 	// Wait for all services to complete
 	wg.Wait()
-	logger.Println("Graceful shutdown completed")
+	logger.Info("graceful shutdown completed")
 }
\ No newline at end of file
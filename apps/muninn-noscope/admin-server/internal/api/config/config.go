@@ -1,32 +1,387 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"admin-server/internal/secrets"
+	"admin-server/internal/util"
+
+	"github.com/BurntSushi/toml"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
+// Config is every setting the server reads at startup. Load layers, from lowest
+// to highest precedence: Default, an optional YAML or TOML file, the environment
+// (including .env) and command-line flags.
 type Config struct {
-	DatabaseURL string
-	JWTSecret   string
+	// File is the config file that was read, if any
+	File     string         `json:"file,omitempty" yaml:"-" toml:"-"`
+	// Mode is what this process runs; see ModeAll
+	Mode     string         `json:"mode" yaml:"mode" toml:"mode"`
+	HTTP     HTTPConfig     `json:"http" yaml:"http" toml:"http"`
+	Database DatabaseConfig `json:"database" yaml:"database" toml:"database"`
+	Auth     AuthConfig     `json:"auth" yaml:"auth" toml:"auth"`
+	Log      LogConfig      `json:"log" yaml:"log" toml:"log"`
+	Worker   WorkerConfig   `json:"worker" yaml:"worker" toml:"worker"`
+	Secrets  SecretsConfig  `json:"secrets" yaml:"secrets" toml:"secrets"`
+	Health   HealthConfig   `json:"health" yaml:"health" toml:"health"`
+	Metrics  MetricsConfig  `json:"metrics" yaml:"metrics" toml:"metrics"`
 }
 
-func Load() (*Config, error) {
-	err := godotenv.Load()
-	if err != nil {
+// Modes split one deployment across processes. ModeAll runs everything; ModeAPI
+// serves the admin API with no background work; ModeWorker runs only the task
+// processor and ModeScheduler only the scheduled scans. Worker and scheduler
+// processes still serve /health, /metrics and the /api/worker control routes.
+const (
+	ModeAll       = "all"
+	ModeAPI       = "api"
+	ModeWorker    = "worker"
+	ModeScheduler = "scheduler"
+)
+
+type HTTPConfig struct {
+	Addr            string   `json:"addr" yaml:"addr" toml:"addr"`
+	ShutdownTimeout Duration `json:"shutdown_timeout" yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+type DatabaseConfig struct {
+	URL string `json:"url" yaml:"url" toml:"url"`
+}
+
+type AuthConfig struct {
+	JWTSecret       string   `json:"jwt_secret" yaml:"jwt_secret" toml:"jwt_secret"`
+	AccessTokenTTL  Duration `json:"access_token_ttl" yaml:"access_token_ttl" toml:"access_token_ttl"`
+	RefreshTokenTTL Duration `json:"refresh_token_ttl" yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+	// AdminEmail and AdminPassword create the first user while the users table is empty
+	AdminEmail    string `json:"admin_email" yaml:"admin_email" toml:"admin_email"`
+	AdminPassword string `json:"admin_password" yaml:"admin_password" toml:"admin_password"`
+}
+
+// LogConfig controls the level and the rotating log files; zero limits are disabled
+type LogConfig struct {
+	Level       string   `json:"level" yaml:"level" toml:"level"`
+	Directory   string   `json:"directory" yaml:"directory" toml:"directory"`
+	MaxSizeMB   int64    `json:"max_size_mb" yaml:"max_size_mb" toml:"max_size_mb"`
+	RotateEvery Duration `json:"rotate_every" yaml:"rotate_every" toml:"rotate_every"`
+	MaxAge      Duration `json:"max_age" yaml:"max_age" toml:"max_age"`
+	MaxFiles    int      `json:"max_files" yaml:"max_files" toml:"max_files"`
+	Compress    bool     `json:"compress" yaml:"compress" toml:"compress"`
+}
+
+type WorkerConfig struct {
+	// ID names this worker in metric labels so its series survive restarts; the
+	// hostname is used when it is empty
+	ID string `json:"id" yaml:"id" toml:"id"`
+	// PollInterval is how often the worker claims a pending task
+	PollInterval Duration `json:"poll_interval" yaml:"poll_interval" toml:"poll_interval"`
+	// ScanInterval is how often Muninn is scanned for new and stale objects
+	ScanInterval Duration `json:"scan_interval" yaml:"scan_interval" toml:"scan_interval"`
+	MaxTags      int      `json:"max_tags" yaml:"max_tags" toml:"max_tags"`
+	// PoolSize caps how many tasks are processed at once
+	PoolSize int `json:"pool_size" yaml:"pool_size" toml:"pool_size"`
+	// StaleDays is how long after its last sync an object is refreshed
+	StaleDays int `json:"stale_days" yaml:"stale_days" toml:"stale_days"`
+	// Rate limits are requests per minute to each upstream; 0 means unlimited
+	NoscopeRateLimit int `json:"noscope_rate_limit" yaml:"noscope_rate_limit" toml:"noscope_rate_limit"`
+	MuninnRateLimit  int `json:"muninn_rate_limit" yaml:"muninn_rate_limit" toml:"muninn_rate_limit"`
+	// DrainTimeout is how long a drain, including the one on shutdown, waits for
+	// in-flight tasks before cancelling them
+	DrainTimeout Duration `json:"drain_timeout" yaml:"drain_timeout" toml:"drain_timeout"`
+	// SettingsReloadInterval is how often runtime settings changed by other
+	// instances are picked up
+	SettingsReloadInterval Duration `json:"settings_reload_interval" yaml:"settings_reload_interval" toml:"settings_reload_interval"`
+}
+
+// SecretsConfig locates the secrets backends. Dir holds mounted secret files and
+// EncryptionKey enables the encrypted database backend; either may be empty.
+type SecretsConfig struct {
+	Dir            string   `json:"dir" yaml:"dir" toml:"dir"`
+	EncryptionKey  string   `json:"encryption_key" yaml:"encryption_key" toml:"encryption_key"`
+	ReloadInterval Duration `json:"reload_interval" yaml:"reload_interval" toml:"reload_interval"`
+}
+
+type HealthConfig struct {
+	// CodeFolder is the checkout whose branch and last pull GET /health reports
+	CodeFolder string `json:"code_folder" yaml:"code_folder" toml:"code_folder"`
+}
+
+// MetricsConfig controls GET /metrics, which needs a viewer or an API key with
+// the metrics:read scope (Prometheus can send it with http_headers) unless Public
+type MetricsConfig struct {
+	Public bool `json:"public" yaml:"public" toml:"public"`
+}
+
+// Default returns the settings used when nothing overrides them
+func Default() *Config {
+	return &Config{
+		Mode: ModeAll,
+		HTTP: HTTPConfig{
+			Addr:            ":8181",
+			ShutdownTimeout: Duration(30 * time.Second),
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  Duration(15 * time.Minute),
+			RefreshTokenTTL: Duration(7 * 24 * time.Hour),
+		},
+		Log: LogConfig{
+			Level:       "info",
+			Directory:   "./logs",
+			MaxSizeMB:   100,
+			RotateEvery: Duration(24 * time.Hour),
+			MaxAge:      Duration(30 * 24 * time.Hour),
+			MaxFiles:    30,
+			Compress:    true,
+		},
+		Worker: WorkerConfig{
+			PollInterval:           Duration(5 * time.Second),
+			ScanInterval:           Duration(5 * time.Minute),
+			MaxTags:                20,
+			PoolSize:               4,
+			StaleDays:              60,
+			DrainTimeout:           Duration(2 * time.Minute),
+			SettingsReloadInterval: Duration(30 * time.Second),
+		},
+		Secrets: SecretsConfig{
+			ReloadInterval: Duration(30 * time.Second),
+		},
+	}
+}
+
+// Load builds the configuration from args (usually os.Args[1:]) and the
+// environment, and validates it. A .env file is read when present.
+func Load(args []string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return nil, fmt.Errorf("failed to load .env file: %v", err)
 	}
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		return nil, fmt.Errorf("DATABASE_URL environment variable is not set")
+
+	flags := flag.NewFlagSet("admin-server", flag.ContinueOnError)
+	file := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config `file` (env CONFIG_FILE)")
+	addr := flags.String("addr", "", "listen `address`, e.g. :8181 (env HTTP_ADDR)")
+	logLevel := flags.String("log-level", "", "debug, info, warn or error (env LOG_LEVEL)")
+	logDir := flags.String("log-dir", "", "log file `directory` (env LOG_DIR)")
+	mode := flags.String("mode", "", "all, api, worker or scheduler (env MODE)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
 	}
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		return nil, fmt.Errorf("JWT_SECRET environment variable is not set")
+
+	cfg := Default()
+	if *file != "" {
+		if err := cfg.loadFile(*file); err != nil {
+			return nil, err
+		}
 	}
-	return &Config{
-		DatabaseURL: dbURL,
-		JWTSecret:   jwtSecret,
-	}, nil
-}
\ No newline at end of file
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.HTTP.Addr = *addr
+		case "log-level":
+			cfg.Log.Level = *logLevel
+		case "log-dir":
+			cfg.Log.Directory = *logDir
+		case "mode":
+			cfg.Mode = *mode
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile decodes a YAML or TOML file, chosen by extension, over the current
+// values. Unknown keys are rejected so a misspelt setting is not silently ignored.
+func (c *Config) loadFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(raw))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(raw), c)
+		if err != nil {
+			return fmt.Errorf("parse config file %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("parse config file %s: unknown keys %v", path, undecoded)
+		}
+	default:
+		return fmt.Errorf("config file %s: expected a .yaml, .yml or .toml extension", path)
+	}
+	c.File = path
+	return nil
+}
+
+// loadEnv applies the environment variables that are set and reports every invalid one
+func (c *Config) loadEnv() error {
+	e := envReader{}
+	e.string("MODE", &c.Mode)
+	e.string("HTTP_ADDR", &c.HTTP.Addr)
+	e.duration("SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout)
+	e.string("DATABASE_URL", &c.Database.URL)
+	e.string("JWT_SECRET", &c.Auth.JWTSecret)
+	e.duration("ACCESS_TOKEN_TTL", &c.Auth.AccessTokenTTL)
+	e.duration("REFRESH_TOKEN_TTL", &c.Auth.RefreshTokenTTL)
+	e.string("ADMIN_EMAIL", &c.Auth.AdminEmail)
+	e.string("ADMIN_PASSWORD", &c.Auth.AdminPassword)
+	e.string("LOG_LEVEL", &c.Log.Level)
+	e.string("LOG_DIR", &c.Log.Directory)
+	e.int64("LOG_MAX_SIZE_MB", &c.Log.MaxSizeMB)
+	e.duration("LOG_ROTATE_EVERY", &c.Log.RotateEvery)
+	e.duration("LOG_MAX_AGE", &c.Log.MaxAge)
+	e.int("LOG_MAX_FILES", &c.Log.MaxFiles)
+	e.bool("LOG_COMPRESS", &c.Log.Compress)
+	e.string("WORKER_ID", &c.Worker.ID)
+	e.seconds("TASK_SLEEP_IN_SECONDS", &c.Worker.PollInterval)
+	e.duration("SCAN_INTERVAL", &c.Worker.ScanInterval)
+	e.int("MUNINN_MAX_TAGS", &c.Worker.MaxTags)
+	e.int("WORKER_POOL_SIZE", &c.Worker.PoolSize)
+	e.int("STALE_DAYS", &c.Worker.StaleDays)
+	e.int("NOSCOPE_RATE_LIMIT", &c.Worker.NoscopeRateLimit)
+	e.int("MUNINN_RATE_LIMIT", &c.Worker.MuninnRateLimit)
+	e.duration("WORKER_DRAIN_TIMEOUT", &c.Worker.DrainTimeout)
+	e.duration("SETTINGS_RELOAD_INTERVAL", &c.Worker.SettingsReloadInterval)
+	e.string("SECRETS_DIR", &c.Secrets.Dir)
+	e.string("SECRETS_ENCRYPTION_KEY", &c.Secrets.EncryptionKey)
+	e.duration("SECRETS_RELOAD_INTERVAL", &c.Secrets.ReloadInterval)
+	e.string("CODE_FOLDER", &c.Health.CodeFolder)
+	e.bool("METRICS_PUBLIC", &c.Metrics.Public)
+	return errors.Join(e.errs...)
+}
+
+// Validate reports every problem at once rather than stopping at the first
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	switch c.Mode {
+	case ModeAll, ModeAPI, ModeWorker, ModeScheduler:
+	default:
+		check(false, "invalid mode %q: expected all, api, worker or scheduler", c.Mode)
+	}
+	check(c.HTTP.Addr != "", "http.addr is required")
+	check(c.HTTP.ShutdownTimeout > 0, "http.shutdown_timeout must be positive")
+	check(c.Database.URL != "", "database.url (DATABASE_URL) is required")
+	check(c.Auth.JWTSecret != "", "auth.jwt_secret (JWT_SECRET) is required")
+	check(c.Auth.AccessTokenTTL > 0, "auth.access_token_ttl must be positive")
+	check(c.Auth.RefreshTokenTTL > 0, "auth.refresh_token_ttl must be positive")
+	check((c.Auth.AdminEmail == "") == (c.Auth.AdminPassword == ""), "auth.admin_email and auth.admin_password must be set together")
+
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "invalid log.level %q: expected debug, info, warn or error", c.Log.Level)
+	check(c.Log.Directory != "", "log.directory is required")
+	check(c.Log.MaxSizeMB >= 0, "log.max_size_mb must not be negative")
+	check(c.Log.RotateEvery >= 0, "log.rotate_every must not be negative")
+	check(c.Log.MaxAge >= 0, "log.max_age must not be negative")
+	check(c.Log.MaxFiles >= 0, "log.max_files must not be negative")
+
+	check(c.Worker.PollInterval > 0, "worker.poll_interval must be positive")
+	check(c.Worker.ScanInterval > 0, "worker.scan_interval must be positive")
+	check(c.Worker.MaxTags > 0, "worker.max_tags must be positive")
+	check(c.Worker.PoolSize > 0, "worker.pool_size must be positive")
+	check(c.Worker.StaleDays > 0, "worker.stale_days must be positive")
+	check(c.Worker.NoscopeRateLimit >= 0, "worker.noscope_rate_limit must not be negative")
+	check(c.Worker.MuninnRateLimit >= 0, "worker.muninn_rate_limit must not be negative")
+	check(c.Worker.DrainTimeout > 0, "worker.drain_timeout must be positive")
+	check(c.Worker.SettingsReloadInterval > 0, "worker.settings_reload_interval must be positive")
+
+	if c.Secrets.EncryptionKey != "" {
+		_, err := secrets.ParseKey(c.Secrets.EncryptionKey)
+		check(err == nil, "invalid secrets.encryption_key: %v", err)
+	}
+	check(c.Secrets.ReloadInterval > 0, "secrets.reload_interval must be positive")
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ServesAPI reports whether this process serves the full admin API
+func (c *Config) ServesAPI() bool {
+	return c.Mode == ModeAll || c.Mode == ModeAPI
+}
+
+// RunsProcessor reports whether this process may run the task processor
+func (c *Config) RunsProcessor() bool {
+	return c.Mode == ModeAll || c.Mode == ModeWorker
+}
+
+// RunsScheduler reports whether this process may run the scheduled tasks
+func (c *Config) RunsScheduler() bool {
+	return c.Mode == ModeAll || c.Mode == ModeScheduler
+}
+
+// LogLevel is Log.Level parsed; Validate has already rejected unknown names
+func (c *Config) LogLevel() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(c.Log.Level))
+	return level
+}
+
+// FileLoggerOptions converts Log for util.NewFileLogger
+func (c *Config) FileLoggerOptions() util.FileLoggerOptions {
+	return util.FileLoggerOptions{
+		Directory:   c.Log.Directory,
+		MaxSize:     c.Log.MaxSizeMB << 20,
+		RotateEvery: time.Duration(c.Log.RotateEvery),
+		MaxAge:      time.Duration(c.Log.MaxAge),
+		MaxFiles:    c.Log.MaxFiles,
+		Compress:    c.Log.Compress,
+	}
+}
+
+// SecretsKey is the decoded Secrets.EncryptionKey, or nil when the database backend is disabled
+func (c *Config) SecretsKey() []byte {
+	if c.Secrets.EncryptionKey == "" {
+		return nil
+	}
+	key, _ := secrets.ParseKey(c.Secrets.EncryptionKey)
+	return key
+}
+
+const redacted = "[redacted]"
+
+// Redacted returns a copy that is safe to show: credentials are replaced, and
+// the database URL keeps everything but its password
+func (c *Config) Redacted() Config {
+	out := *c
+	redact := func(s *string) {
+		if *s != "" {
+			*s = redacted
+		}
+	}
+	redact(&out.Auth.JWTSecret)
+	redact(&out.Auth.AdminPassword)
+	redact(&out.Secrets.EncryptionKey)
+	if u, err := url.Parse(out.Database.URL); err == nil && u.Scheme != "" {
+		out.Database.URL = u.Redacted()
+	} else {
+		redact(&out.Database.URL)
+	}
+	return out
+}
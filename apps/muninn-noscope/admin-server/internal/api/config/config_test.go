@@ -0,0 +1,250 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setEnv clears the variables a test reads and then sets the given ones, so
+// values from the developer's shell do not leak in
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, name := range []string{"CONFIG_FILE", "HTTP_ADDR", "LOG_LEVEL", "LOG_DIR", "WORKER_ID", "METRICS_PUBLIC"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("JWT_SECRET", "secret")
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	yamlFile := "http:\n  addr: \":9000\"\nlog:\n  level: debug\n  directory: /from-file\nworker:\n  id: file-worker\n"
+	tomlFile := "[http]\naddr = \":9000\"\n\n[log]\nlevel = \"debug\"\ndirectory = \"/from-file\"\n"
+
+	tests := []struct {
+		name     string
+		file     string
+		fileName string
+		env      map[string]string
+		args     []string
+		wantAddr string
+		wantLvl  string
+		wantDir  string
+		wantID   string
+	}{
+		{
+			name:     "defaults",
+			wantAddr: ":8181",
+			wantLvl:  "info",
+			wantDir:  "./logs",
+		},
+		{
+			name:     "yaml file over defaults",
+			file:     yamlFile,
+			fileName: "config.yaml",
+			wantAddr: ":9000",
+			wantLvl:  "debug",
+			wantDir:  "/from-file",
+			wantID:   "file-worker",
+		},
+		{
+			name:     "toml file over defaults",
+			file:     tomlFile,
+			fileName: "config.toml",
+			wantAddr: ":9000",
+			wantLvl:  "debug",
+			wantDir:  "/from-file",
+		},
+		{
+			name:     "env over file",
+			file:     yamlFile,
+			fileName: "config.yaml",
+			env:      map[string]string{"HTTP_ADDR": ":9100", "LOG_LEVEL": "warn", "WORKER_ID": "env-worker"},
+			wantAddr: ":9100",
+			wantLvl:  "warn",
+			wantDir:  "/from-file",
+			wantID:   "env-worker",
+		},
+		{
+			name:     "flags over env",
+			file:     yamlFile,
+			fileName: "config.yaml",
+			env:      map[string]string{"HTTP_ADDR": ":9100", "LOG_LEVEL": "warn"},
+			args:     []string{"-addr", ":9200", "-log-dir", "/from-flag"},
+			wantAddr: ":9200",
+			wantLvl:  "warn",
+			wantDir:  "/from-flag",
+			wantID:   "file-worker",
+		},
+		{
+			name:     "empty env keeps file value",
+			file:     yamlFile,
+			fileName: "config.yaml",
+			env:      map[string]string{"HTTP_ADDR": ""},
+			wantAddr: ":9000",
+			wantLvl:  "debug",
+			wantDir:  "/from-file",
+			wantID:   "file-worker",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, tt.fileName, tt.file)}, args...)
+			}
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.HTTP.Addr != tt.wantAddr {
+				t.Errorf("HTTP.Addr = %q, want %q", cfg.HTTP.Addr, tt.wantAddr)
+			}
+			if cfg.Log.Level != tt.wantLvl {
+				t.Errorf("Log.Level = %q, want %q", cfg.Log.Level, tt.wantLvl)
+			}
+			if cfg.Log.Directory != tt.wantDir {
+				t.Errorf("Log.Directory = %q, want %q", cfg.Log.Directory, tt.wantDir)
+			}
+			if cfg.Worker.ID != tt.wantID {
+				t.Errorf("Worker.ID = %q, want %q", cfg.Worker.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		fileName string
+		env      map[string]string
+		want     []string
+	}{
+		{
+			name:     "unknown yaml key",
+			file:     "http:\n  adr: \":9000\"\n",
+			fileName: "config.yaml",
+			want:     []string{"adr"},
+		},
+		{
+			name:     "unknown toml key",
+			file:     "[http]\nadr = \":9000\"\n",
+			fileName: "config.toml",
+			want:     []string{"unknown keys"},
+		},
+		{
+			name:     "unsupported extension",
+			file:     "{}",
+			fileName: "config.json",
+			want:     []string{".yaml, .yml or .toml"},
+		},
+		{
+			name: "every invalid env value",
+			env:  map[string]string{"LOG_MAX_FILES": "many", "SHUTDOWN_TIMEOUT": "soon", "METRICS_PUBLIC": "maybe"},
+			want: []string{"LOG_MAX_FILES", "SHUTDOWN_TIMEOUT", "METRICS_PUBLIC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			var args []string
+			if tt.file != "" {
+				args = []string{"-config", writeFile(t, tt.fileName, tt.file)}
+			}
+
+			_, err := Load(args)
+			if err == nil {
+				t.Fatal("Load() error = nil, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Load() error = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		cfg := Default()
+		cfg.Database.URL = "postgres://localhost/test"
+		cfg.Auth.JWTSecret = "secret"
+		return cfg
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			modify: func(*Config) {},
+		},
+		{
+			name: "one problem",
+			modify: func(c *Config) {
+				c.Worker.MaxTags = 0
+			},
+			want: []string{"worker.max_tags must be positive"},
+		},
+		{
+			name: "every problem reported",
+			modify: func(c *Config) {
+				c.Database.URL = ""
+				c.Auth.JWTSecret = ""
+				c.Auth.AdminEmail = "admin@example.com"
+				c.Log.Level = "loud"
+				c.Log.MaxFiles = -1
+				c.Secrets.EncryptionKey = "short"
+			},
+			want: []string{
+				"database.url (DATABASE_URL) is required",
+				"auth.jwt_secret (JWT_SECRET) is required",
+				"auth.admin_email and auth.admin_password must be set together",
+				`invalid log.level "loud"`,
+				"log.max_files must not be negative",
+				"invalid secrets.encryption_key",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %q, want it to mention %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Duration is a time.Duration written as a Go duration string such as "15m" in
+// config files and in GET /config
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// envReader copies set environment variables into config fields, collecting
+// parse errors so they can all be reported together
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) invalid(name, value, expected string) {
+	e.errs = append(e.errs, fmt.Errorf("invalid %s %q: expected %s", name, value, expected))
+}
+
+func (e *envReader) string(name string, dst *string) {
+	if v := os.Getenv(name); v != "" {
+		*dst = v
+	}
+}
+
+func (e *envReader) duration(name string, dst *Duration) {
+	if v := os.Getenv(name); v != "" {
+		if err := dst.UnmarshalText([]byte(v)); err != nil {
+			e.invalid(name, v, "a duration such as 30s or 15m")
+		}
+	}
+}
+
+// seconds reads a whole number of seconds, for variables that predate duration strings
+func (e *envReader) seconds(name string, dst *Duration) {
+	if v := os.Getenv(name); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			e.invalid(name, v, "a whole number of seconds")
+			return
+		}
+		*dst = Duration(time.Duration(n) * time.Second)
+	}
+}
+
+func (e *envReader) int(name string, dst *int) {
+	if v := os.Getenv(name); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			e.invalid(name, v, "an integer")
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) int64(name string, dst *int64) {
+	if v := os.Getenv(name); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			e.invalid(name, v, "an integer")
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) bool(name string, dst *bool) {
+	if v := os.Getenv(name); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			e.invalid(name, v, "true or false")
+			return
+		}
+		*dst = b
+	}
+}
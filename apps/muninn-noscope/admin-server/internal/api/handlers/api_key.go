@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"admin-server/internal/audit"
+	"admin-server/internal/auth"
+	"admin-server/internal/database"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	queries *database.Queries
+	logger  *slog.Logger
+}
+
+func NewAPIKeyHandler(q *database.Queries, l *slog.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		queries: q,
+		logger:  l,
+	}
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// APIKeyResponse describes a key without its digest. Key is only set when a key
+// is created or rotated; it cannot be retrieved again.
+type APIKeyResponse struct {
+	ID         *uuid.UUID `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  *uuid.UUID `json:"created_by"`
+	CreatedAt  *time.Time `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RotatedAt  *time.Time `json:"rotated_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	Key        string     `json:"key,omitempty"`
+}
+
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.queries.ListAPIKeys(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := make([]APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		response = append(response, apiKeyResponse(k, ""))
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"api_keys": response,
+	})
+}
+
+// Create issues a key with the given scopes. expires_at is optional; keys without it never expire.
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		http.Error(w, "name is required", http.StatusBadRequest)
+		return
+	}
+	scopes, err := auth.ParseScopes(req.Scopes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(scopes) == 0 {
+		http.Error(w, "at least one scope is required", http.StatusBadRequest)
+		return
+	}
+	var expiresAt sql.NullTime
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			http.Error(w, "expires_at must be in the future", http.StatusBadRequest)
+			return
+		}
+		expiresAt = sql.NullTime{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	principal, _ := auth.FromContext(r.Context())
+	scopeNames := make([]string, len(scopes))
+	for i, s := range scopes {
+		scopeNames[i] = string(s)
+	}
+
+	created, err := h.queries.CreateAPIKey(r.Context(), database.CreateAPIKeyParams{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopeNames,
+		CreatedBy: &principal.UserID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	audit.SetTarget(r.Context(), "api_key", created.ID.String())
+	h.logger.InfoContext(r.Context(), "api key created", "api_key_id", created.ID, "prefix", prefix, "scopes", scopeNames)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(apiKeyResponse(created, key))
+}
+
+// Rotate replaces a key's secret, keeping its name, scopes and expiry. The old secret stops working at once.
+func (h *APIKeyHandler) Rotate(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid api key id", http.StatusBadRequest)
+		return
+	}
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rotated, err := h.queries.RotateAPIKey(r.Context(), database.RotateAPIKeyParams{
+		ID:      &id,
+		Prefix:  prefix,
+		KeyHash: hash,
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "api key not found or revoked", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "api key rotated", "api_key_id", rotated.ID, "prefix", prefix)
+	json.NewEncoder(w).Encode(apiKeyResponse(rotated, key))
+}
+
+func (h *APIKeyHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid api key id", http.StatusBadRequest)
+		return
+	}
+	revoked, err := h.queries.RevokeAPIKey(r.Context(), &id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "api key not found or already revoked", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "api key revoked", "api_key_id", revoked.ID)
+	json.NewEncoder(w).Encode(apiKeyResponse(revoked, ""))
+}
+
+func apiKeyResponse(k database.ApiKey, key string) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  nullTimePtr(k.CreatedAt),
+		ExpiresAt:  nullTimePtr(k.ExpiresAt),
+		LastUsedAt: nullTimePtr(k.LastUsedAt),
+		RotatedAt:  nullTimePtr(k.RotatedAt),
+		RevokedAt:  nullTimePtr(k.RevokedAt),
+		Key:        key,
+	}
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
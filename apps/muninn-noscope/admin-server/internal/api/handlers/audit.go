@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"admin-server/internal/database"
+
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	queries *database.Queries
+	logger  *slog.Logger
+}
+
+func NewAuditHandler(q *database.Queries, l *slog.Logger) *AuditHandler {
+	return &AuditHandler{
+		queries: q,
+		logger:  l,
+	}
+}
+
+type AuditEventResponse struct {
+	ID           *uuid.UUID      `json:"id"`
+	OccurredAt   time.Time       `json:"occurred_at"`
+	ActorType    string          `json:"actor_type"`
+	ActorID      *uuid.UUID      `json:"actor_id"`
+	ActorName    *string         `json:"actor_name"`
+	Action       string          `json:"action"`
+	TargetType   *string         `json:"target_type"`
+	TargetID     *string         `json:"target_id"`
+	RequestID    *string         `json:"request_id"`
+	Method       *string         `json:"method"`
+	Path         *string         `json:"path"`
+	Payload      json.RawMessage `json:"payload"`
+	IP           *string         `json:"ip"`
+	ForwardedFor *string         `json:"forwarded_for"`
+	Result       string          `json:"result"`
+	StatusCode   *int32          `json:"status_code"`
+	Error        *string         `json:"error"`
+}
+
+// List returns audit events, newest first unless order=asc. Filters: actor_type,
+// actor_id, action (repeated or comma-separated), target_type, target_id, result,
+// and an occurred_after/occurred_before window.
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, countMode, err := queryPage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter, err := parseAuditFilter(r, page.Cursor)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, cursors, err := h.queries.SearchAuditEvents(r.Context(), filter, page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	count, err := h.queries.CountSearchAuditEvents(r.Context(), filter, countMode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		response = append(response, auditEventResponse(e))
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events":     response,
+		"pagination": paginationResponse(page, countMode, count, cursors),
+	})
+}
+
+func parseAuditFilter(r *http.Request, cursor *database.Cursor) (database.AuditFilter, error) {
+	var filter database.AuditFilter
+	var err error
+	query := r.URL.Query()
+
+	if v := query.Get("actor_type"); v != "" {
+		if !slices.Contains(database.AuditActorTypes, v) {
+			return filter, fmt.Errorf("invalid actor_type %q", v)
+		}
+		filter.ActorType = v
+	}
+	if v := query.Get("actor_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return filter, fmt.Errorf("invalid actor_id")
+		}
+		filter.ActorID = &id
+	}
+	for _, a := range query["action"] {
+		for _, action := range strings.Split(a, ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				filter.Actions = append(filter.Actions, action)
+			}
+		}
+	}
+	filter.TargetType = strings.TrimSpace(query.Get("target_type"))
+	filter.TargetID = strings.TrimSpace(query.Get("target_id"))
+	if v := query.Get("result"); v != "" {
+		if !slices.Contains(database.AuditResults, v) {
+			return filter, fmt.Errorf("invalid result %q", v)
+		}
+		filter.Result = v
+	}
+	if filter.Occurred.After, err = queryTime(r, "occurred_after"); err != nil {
+		return filter, err
+	}
+	if filter.Occurred.Before, err = queryTime(r, "occurred_before"); err != nil {
+		return filter, err
+	}
+
+	if cursor != nil {
+		if cursor.Sort != "occurred_at" {
+			return filter, fmt.Errorf("invalid cursor")
+		}
+		filter.SortAsc = cursor.Asc
+		return filter, nil
+	}
+	filter.SortAsc, err = queryOrderAsc(r)
+	return filter, err
+}
+
+func auditEventResponse(e database.AuditEvent) AuditEventResponse {
+	response := AuditEventResponse{
+		ID:           e.ID,
+		OccurredAt:   e.OccurredAt,
+		ActorType:    e.ActorType,
+		ActorID:      e.ActorID,
+		ActorName:    nullStringPtr(e.ActorName),
+		Action:       e.Action,
+		TargetType:   nullStringPtr(e.TargetType),
+		TargetID:     nullStringPtr(e.TargetID),
+		RequestID:    nullStringPtr(e.RequestID),
+		Method:       nullStringPtr(e.Method),
+		Path:         nullStringPtr(e.Path),
+		IP:           nullStringPtr(e.Ip),
+		ForwardedFor: nullStringPtr(e.ForwardedFor),
+		Result:       e.Result,
+		Error:        nullStringPtr(e.Error),
+	}
+	if e.Payload.Valid {
+		response.Payload = e.Payload.RawMessage
+	}
+	if e.StatusCode.Valid {
+		response.StatusCode = &e.StatusCode.Int32
+	}
+	return response
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -1,38 +1,204 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
+	"time"
 
+	"admin-server/internal/audit"
+	"admin-server/internal/auth"
 	"admin-server/internal/database"
+
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
-	queries *database.Queries
-	logger  *log.Logger
+	queries    *database.Queries
+	issuer     *auth.Issuer
+	refreshTTL time.Duration
+	logger     *slog.Logger
 }
 
-func NewAuthHandler(q *database.Queries, l *log.Logger) *AuthHandler {
+func NewAuthHandler(q *database.Queries, issuer *auth.Issuer, refreshTTL time.Duration, l *slog.Logger) *AuthHandler {
 	return &AuthHandler{
-		queries: q,
-		logger:  l,
+		queries:    q,
+		issuer:     issuer,
+		refreshTTL: refreshTTL,
+		logger:     l,
 	}
 }
 
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// UserResponse is the public view of a user; the password hash is never returned
+type UserResponse struct {
+	ID          *uuid.UUID `json:"id"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Disabled    bool       `json:"disabled"`
+	CreatedAt   *time.Time `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+type TokenResponse struct {
+	AccessToken      string       `json:"access_token"`
+	TokenType        string       `json:"token_type"`
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RefreshToken     string       `json:"refresh_token"`
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+	User             UserResponse `json:"user"`
+}
+
+// Login checks an email and password and starts a session. The response carries
+// a short-lived access token for the Authorization header and a refresh token.
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	reqUserID := r.Header.Get("x-user-secret")
-	secret := os.Getenv("ADMIN_SECRET")
-	if reqUserID == "" || reqUserID != secret {
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return
-	}
-	muninn_jwt := os.Getenv("MUNINN_JWT")
-	control_secret := os.Getenv("CONTROL_SECRET_KEY")
-	json.NewEncoder(w).Encode(map[string]string{
-		"status": "ok",
-		"muninn_jwt": muninn_jwt,
-		"control_secret": control_secret,
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.queries.GetUserByEmail(r.Context(), auth.NormalizeEmail(req.Email))
+	if err != nil && err != sql.ErrNoRows {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	// Unknown users are checked against a dummy hash so both failures look the same
+	if !auth.CheckPassword(user.PasswordHash, req.Password) || user.Disabled {
+		h.logger.WarnContext(r.Context(), "login failed", "email", req.Email)
+		http.Error(w, "invalid email or password", http.StatusUnauthorized)
+		return
+	}
+
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	session, err := h.queries.CreateSession(r.Context(), database.CreateSessionParams{
+		UserID:           user.ID,
+		RefreshTokenHash: refreshHash,
+		UserAgent:        sql.NullString{String: r.UserAgent(), Valid: r.UserAgent() != ""},
+		ExpiresAt:        time.Now().Add(h.refreshTTL),
 	})
-}
\ No newline at end of file
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := h.queries.UpdateUserLastLogin(r.Context(), user.ID); err != nil {
+		h.logger.ErrorContext(r.Context(), "update last login failed", "user_id", user.ID, "error", err)
+	}
+
+	audit.SetActor(r.Context(), audit.ActorUser, *user.ID, user.Email)
+	audit.SetTarget(r.Context(), "session", session.ID.String())
+	h.logger.InfoContext(r.Context(), "login", "user_id", user.ID, "session_id", session.ID)
+	h.writeTokens(w, user, session, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new access token. The refresh token is
+// rotated on every use, so a replayed token is rejected.
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.RefreshToken == "" {
+		http.Error(w, "missing refresh_token", http.StatusBadRequest)
+		return
+	}
+
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	session, err := h.queries.RotateSessionRefreshToken(r.Context(), database.RotateSessionRefreshTokenParams{
+		NewHash:   refreshHash,
+		ExpiresAt: time.Now().Add(h.refreshTTL),
+		OldHash:   auth.HashRefreshToken(req.RefreshToken),
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "invalid or expired refresh token", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	user, err := h.queries.GetUser(r.Context(), session.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if user.Disabled {
+		if err := h.queries.RevokeSession(r.Context(), session.ID); err != nil {
+			h.logger.ErrorContext(r.Context(), "revoke session failed", "session_id", session.ID, "error", err)
+		}
+		http.Error(w, "invalid or expired refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	audit.SetActor(r.Context(), audit.ActorUser, *user.ID, user.Email)
+	audit.SetTarget(r.Context(), "session", session.ID.String())
+	h.writeTokens(w, user, session, refreshToken)
+}
+
+// Logout revokes the caller's session; its access and refresh tokens stop working immediately
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	principal, _ := auth.FromContext(r.Context())
+	audit.SetTarget(r.Context(), "session", principal.SessionID.String())
+	if err := h.queries.RevokeSession(r.Context(), &principal.SessionID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.logger.InfoContext(r.Context(), "logout", "session_id", principal.SessionID)
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// Me returns the user behind the access token
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	principal, _ := auth.FromContext(r.Context())
+	user, err := h.queries.GetUser(r.Context(), &principal.UserID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(userResponse(user))
+}
+
+func (h *AuthHandler) writeTokens(w http.ResponseWriter, user database.User, session database.Session, refreshToken string) {
+	accessToken, expiresAt, err := h.issuer.Issue(*user.ID, *session.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(TokenResponse{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             userResponse(user),
+	})
+}
+
+func userResponse(u database.User) UserResponse {
+	return UserResponse{
+		ID:          u.ID,
+		Email:       u.Email,
+		Role:        u.Role,
+		Disabled:    u.Disabled,
+		CreatedAt:   nullTimePtr(u.CreatedAt),
+		LastLoginAt: nullTimePtr(u.LastLoginAt),
+	}
+}
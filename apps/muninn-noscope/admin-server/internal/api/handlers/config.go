@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"admin-server/internal/api/config"
+)
+
+type ConfigHandler struct {
+	cfg *config.Config
+}
+
+func NewConfigHandler(cfg *config.Config) *ConfigHandler {
+	return &ConfigHandler{cfg: cfg}
+}
+
+// Get returns the effective configuration with credentials redacted
+func (h *ConfigHandler) Get(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(h.cfg.Redacted())
+}
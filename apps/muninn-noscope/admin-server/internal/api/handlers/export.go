@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"admin-server/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// exportFlushEvery is how many rows are written between flushes to the client
+const exportFlushEvery = 500
+
+// Flattened columns pick a dotted path out of a task's input or output, e.g. output.organisation
+var flattenedColumnPattern = regexp.MustCompile(`^(input|output)(\.[A-Za-z0-9_]+)+$`)
+
+var taskExportFields = map[string]func(*database.Task) interface{}{
+	"id":              func(t *database.Task) interface{} { return uuidValue(t.ID) },
+	"object_id":       func(t *database.Task) interface{} { return uuidValue(t.ObjectID) },
+	"status":          func(t *database.Task) interface{} { return t.Status },
+	"source":          func(t *database.Task) interface{} { return t.Source },
+	"error":           func(t *database.Task) interface{} { return nullStringValue(t.Error) },
+	"created_at":      func(t *database.Task) interface{} { return nullTimeValue(t.CreatedAt) },
+	"started_at":      func(t *database.Task) interface{} { return nullTimeValue(t.StartedAt) },
+	"completed_at":    func(t *database.Task) interface{} { return nullTimeValue(t.CompletedAt) },
+	"duration":        taskDuration,
+	"mapping_version": func(t *database.Task) interface{} { return nullStringValue(t.MappingVersion) },
+	"input": func(t *database.Task) interface{} {
+		return rawJSONValue(pqtype.NullRawMessage{RawMessage: t.Input, Valid: true})
+	},
+	"output": func(t *database.Task) interface{} { return rawJSONValue(t.Output) },
+}
+
+var defaultTaskExportColumns = []string{"id", "object_id", "status", "source", "created_at", "started_at", "completed_at", "error"}
+
+// Object exports describe the latest task with latest_* columns; flattened columns read its input/output
+var objectExportFields = map[string]func(*database.ObjectWithLatestTask) interface{}{
+	"id":             func(o *database.ObjectWithLatestTask) interface{} { return uuidValue(o.ID) },
+	"created_at":     func(o *database.ObjectWithLatestTask) interface{} { return nullTimeValue(o.CreatedAt) },
+	"last_synced_at": func(o *database.ObjectWithLatestTask) interface{} { return nullTimeValue(o.LastSyncedAt) },
+	"content_hash":   func(o *database.ObjectWithLatestTask) interface{} { return nullStringValue(o.ContentHash) },
+	"latest_task_id": latestTaskField("id"),
+	"latest_status":  latestTaskField("status"),
+	"latest_source":  latestTaskField("source"),
+	"latest_error":   latestTaskField("error"),
+	"latest_task_at": latestTaskField("created_at"),
+}
+
+var defaultObjectExportColumns = []string{"id", "created_at", "last_synced_at", "latest_status", "latest_task_at"}
+
+type ExportHandler struct {
+	queries *database.Queries
+	db      *sql.DB
+	logger  *slog.Logger
+}
+
+func NewExportHandler(q *database.Queries, db *sql.DB, l *slog.Logger) *ExportHandler {
+	return &ExportHandler{
+		queries: q,
+		db:      db,
+		logger:  l,
+	}
+}
+
+// Tasks streams every task matching the /tasks filters as CSV or NDJSON
+func (h *ExportHandler) Tasks(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseTaskFilter(r, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	columns, err := queryExportColumns(r, defaultTaskExportColumns, taskExportFields)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.stream(w, r, "tasks", columns, func(q *database.Queries, write func([]interface{}) error) error {
+		return q.ExportTasks(r.Context(), filter, func(t database.Task) error {
+			return write(taskExportRow(&t, columns))
+		})
+	})
+}
+
+// Objects streams every object matching the /objects filters as CSV or NDJSON
+func (h *ExportHandler) Objects(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseObjectFilter(r, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	columns, err := queryExportColumns(r, defaultObjectExportColumns, objectExportFields)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.stream(w, r, "objects", columns, func(q *database.Queries, write func([]interface{}) error) error {
+		return q.ExportObjects(r.Context(), filter, func(o database.ObjectWithLatestTask) error {
+			return write(objectExportRow(&o, columns))
+		})
+	})
+}
+
+// stream runs export inside a read-only transaction and writes each row as it arrives.
+// Once the first byte is sent the status can no longer change, so later errors are only logged.
+func (h *ExportHandler) stream(w http.ResponseWriter, r *http.Request, name string, columns []string, export func(*database.Queries, func([]interface{}) error) error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	var out exportWriter
+	switch format {
+	case "csv":
+		out = &csvExportWriter{w: csv.NewWriter(w), columns: columns}
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	case "ndjson":
+		out = &ndjsonExportWriter{w: w, columns: columns}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	default:
+		http.Error(w, fmt.Sprintf("invalid format %q: expected csv or ndjson", format), http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.db.BeginTx(r.Context(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback()
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102T150405Z"), format)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	flusher, _ := w.(http.Flusher)
+
+	if err := out.WriteHeader(); err != nil {
+		h.logger.ErrorContext(r.Context(), "export failed", "export", name, "error", err)
+		return
+	}
+	written := 0
+	err = export(h.queries.WithTx(tx), func(values []interface{}) error {
+		if err := out.WriteRow(values); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushEvery == 0 {
+			if err := out.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "export failed", "export", name, "rows", written, "error", err)
+	}
+	out.Flush()
+}
+
+// queryExportColumns parses the comma-separated columns parameter against the known fields
+func queryExportColumns[T any](r *http.Request, defaults []string, fields map[string]T) ([]string, error) {
+	param := r.URL.Query().Get("columns")
+	if param == "" {
+		return defaults, nil
+	}
+	var columns []string
+	for _, c := range strings.Split(param, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		if _, ok := fields[c]; !ok && !flattenedColumnPattern.MatchString(c) {
+			return nil, fmt.Errorf("invalid column %q", c)
+		}
+		columns = append(columns, c)
+	}
+	if len(columns) == 0 {
+		return defaults, nil
+	}
+	return columns, nil
+}
+
+func taskExportRow(t *database.Task, columns []string) []interface{} {
+	var docs map[string]interface{}
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		if field, ok := taskExportFields[c]; ok {
+			values[i] = field(t)
+			continue
+		}
+		// Decode input/output once per row, and only when a flattened column asks for it
+		if docs == nil {
+			docs = map[string]interface{}{
+				"input":  decodeJSON(t.Input),
+				"output": decodeJSON(t.Output.RawMessage),
+			}
+		}
+		source, path, _ := strings.Cut(c, ".")
+		values[i] = lookupJSONPath(docs[source], strings.Split(path, "."))
+	}
+	return values
+}
+
+func objectExportRow(o *database.ObjectWithLatestTask, columns []string) []interface{} {
+	var taskValues []interface{}
+	values := make([]interface{}, len(columns))
+	for i, c := range columns {
+		if field, ok := objectExportFields[c]; ok {
+			values[i] = field(o)
+			continue
+		}
+		if o.LatestTask == nil {
+			continue
+		}
+		if taskValues == nil {
+			taskValues = taskExportRow(o.LatestTask, columns)
+		}
+		values[i] = taskValues[i]
+	}
+	return values
+}
+
+func latestTaskField(name string) func(*database.ObjectWithLatestTask) interface{} {
+	field := taskExportFields[name]
+	return func(o *database.ObjectWithLatestTask) interface{} {
+		if o.LatestTask == nil {
+			return nil
+		}
+		return field(o.LatestTask)
+	}
+}
+
+func taskDuration(t *database.Task) interface{} {
+	if !t.StartedAt.Valid || !t.CompletedAt.Valid {
+		return nil
+	}
+	return t.CompletedAt.Time.Sub(t.StartedAt.Time).Seconds()
+}
+
+func decodeJSON(raw []byte) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil
+	}
+	return doc
+}
+
+func lookupJSONPath(doc interface{}, path []string) interface{} {
+	for _, key := range path {
+		obj, ok := doc.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		doc = obj[key]
+	}
+	return doc
+}
+
+func uuidValue(id *uuid.UUID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.String()
+}
+
+func nullStringValue(s sql.NullString) interface{} {
+	if !s.Valid {
+		return nil
+	}
+	return s.String
+}
+
+func nullTimeValue(t sql.NullTime) interface{} {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time
+}
+
+func rawJSONValue(m pqtype.NullRawMessage) interface{} {
+	if !m.Valid || len(m.RawMessage) == 0 {
+		return nil
+	}
+	return m.RawMessage
+}
+
+type exportWriter interface {
+	WriteHeader() error
+	WriteRow(values []interface{}) error
+	Flush() error
+}
+
+type csvExportWriter struct {
+	w       *csv.Writer
+	columns []string
+}
+
+func (c *csvExportWriter) WriteHeader() error {
+	return c.w.Write(c.columns)
+}
+
+func (c *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = csvValue(v)
+		if _, isNumber := v.(float64); !isNumber {
+			record[i] = escapeCSVFormula(record[i])
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvExportWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// escapeCSVFormula prefixes cells a spreadsheet would evaluate as a formula with '.
+// Numbers are written by us and left alone so negative values stay numeric.
+func escapeCSVFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvValue renders a cell; nested JSON values are written as compact JSON
+func csvValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case json.RawMessage:
+		return string(v)
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
+
+type ndjsonExportWriter struct {
+	w       http.ResponseWriter
+	columns []string
+}
+
+func (n *ndjsonExportWriter) WriteHeader() error {
+	return nil
+}
+
+// WriteRow writes one JSON object per line, keeping keys in column order
+func (n *ndjsonExportWriter) WriteRow(values []interface{}) error {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(n.columns[i])
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteString("}\n")
+	_, err := n.w.Write([]byte(b.String()))
+	return err
+}
+
+func (n *ndjsonExportWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestCSVValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{name: "nil", in: nil, want: ""},
+		{name: "string", in: "Acme", want: "Acme"},
+		{name: "time", in: time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC), want: "2024-03-01T12:30:00Z"},
+		{name: "integral float", in: 42.0, want: "42"},
+		{name: "fractional float", in: -0.25, want: "-0.25"},
+		{name: "large float", in: 1e21, want: "1000000000000000000000"},
+		{name: "bool", in: true, want: "true"},
+		{name: "raw JSON", in: json.RawMessage(`{"a":1}`), want: `{"a":1}`},
+		{name: "nested map", in: map[string]interface{}{"b": []interface{}{1.0, "x"}, "a": nil}, want: `{"a":null,"b":[1,"x"]}`},
+		{name: "unmarshalable", in: make(chan int), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := csvValue(tt.in); got != tt.want {
+				t.Errorf("csvValue(%v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeCSVFormula(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "Acme", want: "Acme"},
+		{in: "a=b", want: "a=b"},
+		{in: "=HYPERLINK(\"http://x\")", want: "'=HYPERLINK(\"http://x\")"},
+		{in: "+1", want: "'+1"},
+		{in: "-1", want: "'-1"},
+		{in: "@SUM(A1)", want: "'@SUM(A1)"},
+		{in: "\t=1", want: "'\t=1"},
+		{in: "\r=1", want: "'\r=1"},
+		{in: " =1", want: " =1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := escapeCSVFormula(tt.in); got != tt.want {
+				t.Errorf("escapeCSVFormula(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVExportWriterWriteRow(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		want   string
+	}{
+		{name: "negative number stays numeric", values: []interface{}{-3.5, "ok"}, want: "-3.5,ok\n"},
+		{name: "formula string escaped", values: []interface{}{"=1+1", "-x"}, want: "'=1+1,'-x\n"},
+		{name: "JSON cells start with a quote or bracket", values: []interface{}{json.RawMessage(`"=cmd"`), []interface{}{"=x"}}, want: "\"\"\"=cmd\"\"\",\"[\"\"=x\"\"]\"\n"},
+		{name: "empty cells", values: []interface{}{nil, ""}, want: ",\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			out := &csvExportWriter{w: csv.NewWriter(&buf)}
+			if err := out.WriteRow(tt.values); err != nil {
+				t.Fatalf("WriteRow(): %v", err)
+			}
+			if err := out.Flush(); err != nil {
+				t.Fatalf("Flush(): %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteRow(%v) wrote %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,9 +3,8 @@ package handlers
 import (
 	"admin-server/internal/database"
 	"encoding/json"
-	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -19,20 +18,19 @@ type GitInfo struct {
 
 
 // getGitInfo retrieves the current Git branch and last pull time
-func getGitInfo() (*GitInfo, error) {
-	codeFolder := os.Getenv("CODE_FOLDER")
-	// go to the code folder
-	exec.Command("cd "+codeFolder)
+func getGitInfo(codeFolder string) (*GitInfo, error) {
 	// Get the current branch name
 	branchCmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	branchCmd.Dir = codeFolder
 	branchOutput, err := branchCmd.Output()
 	if err != nil {
-		fmt.Println("err: ", err)
+		slog.Warn("git branch lookup failed", "error", err)
 		return nil, err
 	}
 	branch := strings.TrimSpace(string(branchOutput))
 	// Get the last pull time
 	pullCmd := exec.Command("git", "log", "-1", "--format=%cd", "--date=iso", "@{upstream}")
+	pullCmd.Dir = codeFolder
 	pullOutput, err := pullCmd.Output()
 	if err != nil {
 		return nil, err
@@ -48,7 +46,9 @@ func getGitInfo() (*GitInfo, error) {
 	}, nil
 }
 
-func HealthCheck(db *database.Queries) http.HandlerFunc {
+// HealthCheck reports database connectivity and the git state of codeFolder, the
+// checkout the server was deployed from; an empty codeFolder means the working directory
+func HealthCheck(db *database.Queries, codeFolder string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Check if the database connection is alive
 		_, err := db.HealthCheck(r.Context())
@@ -59,7 +59,7 @@ func HealthCheck(db *database.Queries) http.HandlerFunc {
 		}
 
 		// Get Git information
-		gitInfo, err := getGitInfo()
+		gitInfo, err := getGitInfo(codeFolder)
 		if err != nil {
 			gitInfo = &GitInfo{Branch: "unknown", LastPull: "unknown"}
 		}
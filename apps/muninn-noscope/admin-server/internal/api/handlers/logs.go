@@ -0,0 +1,342 @@
+package handlers
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"admin-server/internal/util"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultLogLines = 500
+	maxLogLines     = 5000
+	// maxLogLineBytes bounds a single line so a corrupt file cannot exhaust memory
+	maxLogLineBytes = 1 << 20
+
+	logTailPoll      = 500 * time.Millisecond
+	logTailHeartbeat = 15 * time.Second
+)
+
+type LogHandler struct {
+	dir    string
+	logger *slog.Logger
+}
+
+func NewLogHandler(dir string, l *slog.Logger) *LogHandler {
+	return &LogHandler{
+		dir:    dir,
+		logger: l,
+	}
+}
+
+type LogFile struct {
+	Name       string    `json:"name"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+	Compressed bool      `json:"compressed"`
+	Current    bool      `json:"current"`
+}
+
+type LogLine struct {
+	Line  int    `json:"line"`
+	Level string `json:"level,omitempty"`
+	Text  string `json:"text"`
+}
+
+// List returns the files written by util.FileLogger, newest first
+func (h *LogHandler) List(w http.ResponseWriter, r *http.Request) {
+	files, err := util.ListLogFiles(h.dir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	current, _ := os.Readlink(filepath.Join(h.dir, util.CurrentLogName))
+
+	response := make([]LogFile, 0, len(files))
+	for _, f := range files {
+		response = append(response, LogFile{
+			Name:       f.Name(),
+			Size:       f.Size(),
+			ModifiedAt: f.ModTime(),
+			Compressed: strings.HasSuffix(f.Name(), ".gz"),
+			Current:    f.Name() == current,
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"files": response})
+}
+
+// Get returns matching lines of one file. from_line (1-based) and limit select a range,
+// or tail=N returns the last N matching lines; see parseLogFilter for the filters.
+func (h *LogHandler) Get(w http.ResponseWriter, r *http.Request) {
+	path, err := h.resolve(chi.URLParam(r, "name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	filter, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fromLine, err := queryInt(r, "from_line", 1)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, err := queryInt(r, "limit", defaultLogLines)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tail, err := queryInt(r, "tail", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if tail > 0 {
+		limit = tail
+	}
+	if limit > maxLogLines {
+		limit = maxLogLines
+	}
+
+	reader, err := openLogFile(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineBytes)
+	lines := []LogLine{}
+	lineNo := 0
+	nextLine := 0
+	for scanner.Scan() {
+		lineNo++
+		if tail == 0 && lineNo < fromLine {
+			continue
+		}
+		level, ok := filter.match(scanner.Text())
+		if !ok {
+			continue
+		}
+		if tail == 0 && len(lines) == limit {
+			nextLine = lineNo
+			break
+		}
+		lines = append(lines, LogLine{Line: lineNo, Level: level, Text: scanner.Text()})
+		if tail > 0 && len(lines) > limit {
+			lines = lines[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"file":  filepath.Base(path),
+		"lines": lines,
+	}
+	if nextLine > 0 {
+		response["next_line"] = nextLine
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// Tail streams new lines of the current log file as Server-Sent Events, following
+// rotation. It accepts the same filters as Get.
+func (h *LogHandler) Tail(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	filter, err := parseLogFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	link := filepath.Join(h.dir, util.CurrentLogName)
+	target, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		http.Error(w, "no current log file", http.StatusNotFound)
+		return
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer func() { file.Close() }()
+	// Only lines written after the client connects are sent
+	if _, err := file.Seek(0, io.SeekEnd); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	fmt.Fprintf(w, "event: open\ndata: %s\n\n", filepath.Base(target))
+	flusher.Flush()
+
+	reader := bufio.NewReader(file)
+	partial := ""
+	drain := func() {
+		for {
+			chunk, err := reader.ReadString('\n')
+			if err != nil {
+				// Keep an incomplete last line until the rest is written
+				partial += chunk
+				return
+			}
+			line := strings.TrimRight(partial+chunk, "\r\n")
+			partial = ""
+			if _, ok := filter.match(line); ok {
+				fmt.Fprintf(w, "event: log\ndata: %s\n\n", line)
+			}
+		}
+	}
+	poll := time.NewTicker(logTailPoll)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(logTailHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case <-poll.C:
+			drain()
+			// On rotation, read whatever reached the old file last, then follow the symlink
+			if next, err := filepath.EvalSymlinks(link); err == nil && next != target {
+				newFile, err := os.Open(next)
+				if err == nil {
+					drain()
+					file.Close()
+					file, target, partial = newFile, next, ""
+					reader = bufio.NewReader(file)
+					fmt.Fprintf(w, "event: rotate\ndata: %s\n\n", filepath.Base(target))
+				}
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// resolve maps a file name from the URL to a path inside the log directory
+func (h *LogHandler) resolve(name string) (string, error) {
+	if name == util.CurrentLogName {
+		return filepath.EvalSymlinks(filepath.Join(h.dir, name))
+	}
+	if name != filepath.Base(name) || !util.IsLogFileName(name) {
+		return "", fmt.Errorf("log file not found")
+	}
+	path := filepath.Join(h.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("log file not found")
+	}
+	return path, nil
+}
+
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g gzipFile) Close() error {
+	g.Reader.Close()
+	return g.file.Close()
+}
+
+func openLogFile(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return file, nil
+	}
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return gzipFile{Reader: gz, file: file}, nil
+}
+
+// logFilter selects lines by minimum level, case-insensitive text and exact
+// correlation fields. Level and field filters only match JSON lines.
+type logFilter struct {
+	minLevel *slog.Level
+	text     string
+	fields   map[string]string
+}
+
+// Correlation fields that can be filtered on directly, e.g. ?task_id=...
+var logFilterFields = []string{"request_id", "task_id", "object_id", "worker_id"}
+
+func parseLogFilter(r *http.Request) (logFilter, error) {
+	query := r.URL.Query()
+	filter := logFilter{
+		text:   strings.ToLower(query.Get("q")),
+		fields: map[string]string{},
+	}
+	if v := query.Get("level"); v != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(v)); err != nil {
+			return filter, fmt.Errorf("invalid level %q: expected debug, info, warn or error", v)
+		}
+		filter.minLevel = &level
+	}
+	for _, field := range logFilterFields {
+		if v := query.Get(field); v != "" {
+			filter.fields[field] = v
+		}
+	}
+	return filter, nil
+}
+
+// match reports whether line passes the filter, and its level when it is a JSON line
+func (f logFilter) match(line string) (string, bool) {
+	if f.text != "" && !strings.Contains(strings.ToLower(line), f.text) {
+		return "", false
+	}
+
+	var record map[string]interface{}
+	if !strings.HasPrefix(line, "{") || json.Unmarshal([]byte(line), &record) != nil {
+		return "", f.minLevel == nil && len(f.fields) == 0
+	}
+	level, _ := record["level"].(string)
+	if f.minLevel != nil {
+		var l slog.Level
+		if l.UnmarshalText([]byte(level)) != nil || l < *f.minLevel {
+			return level, false
+		}
+	}
+	for key, want := range f.fields {
+		if got, _ := record[key].(string); got != want {
+			return level, false
+		}
+	}
+	return level, true
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"admin-server/internal/metrics"
+	"admin-server/internal/muninn"
+	"admin-server/internal/secrets"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// MuninnHandler lets the admin UI look up Muninn objects with the server's
+// credentials, which are never sent to the browser
+type MuninnHandler struct {
+	client  *muninn.Client
+	secrets *secrets.Store
+	logger  *slog.Logger
+}
+
+func NewMuninnHandler(client *muninn.Client, store *secrets.Store, l *slog.Logger) *MuninnHandler {
+	return &MuninnHandler{
+		client:  client,
+		secrets: store,
+		logger:  l,
+	}
+}
+
+// SearchObjects forwards search, page and pageSize to MUNINN_SEARCH_OBJECTS_URL
+func (h *MuninnHandler) SearchObjects(w http.ResponseWriter, r *http.Request) {
+	base := h.secrets.Get(secrets.MuninnSearchObjectsURL)
+	if base == "" {
+		http.Error(w, fmt.Sprintf("%s is not configured", secrets.MuninnSearchObjectsURL), http.StatusServiceUnavailable)
+		return
+	}
+	target, err := url.Parse(base)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid %s: %v", secrets.MuninnSearchObjectsURL, err), http.StatusInternalServerError)
+		return
+	}
+	query := target.Query()
+	for _, name := range []string{"search", "page", "pageSize"} {
+		if v := r.URL.Query().Get(name); v != "" {
+			query.Set(name, v)
+		}
+	}
+	target.RawQuery = query.Encode()
+	h.forward(w, r, "muninn_search", target.String())
+}
+
+// GetObject returns a Muninn object from MUNINN_OBJECT_URL
+func (h *MuninnHandler) GetObject(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid object id", http.StatusBadRequest)
+		return
+	}
+	base := h.secrets.Get(secrets.MuninnObjectURL)
+	if base == "" {
+		http.Error(w, fmt.Sprintf("%s is not configured", secrets.MuninnObjectURL), http.StatusServiceUnavailable)
+		return
+	}
+	h.forward(w, r, "muninn_object", strings.TrimSuffix(base, "/")+"/"+id.String())
+}
+
+// forward relays Muninn's status and body. A 401 from Muninn becomes a 502 so
+// the UI does not mistake it for its own session expiring.
+func (h *MuninnHandler) forward(w http.ResponseWriter, r *http.Request, upstream, target string) {
+	req, err := http.NewRequestWithContext(metrics.WithUpstream(r.Context(), upstream), http.MethodGet, target, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "muninn request failed", "upstream", upstream, "error", err)
+		http.Error(w, "muninn request failed", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	status := resp.StatusCode
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		status = http.StatusBadGateway
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.WriteHeader(status)
+	io.Copy(w, resp.Body)
+}
@@ -1,66 +1,288 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"strconv"
+	"time"
 
 	"admin-server/internal/database"
+	"admin-server/internal/settings"
+	"admin-server/internal/worker"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type ObjectHandler struct {
-	queries *database.Queries
-	logger  *log.Logger
+	queries  *database.Queries
+	manager  *worker.Manager
+	settings *settings.Store
+	logger   *slog.Logger
 }
 
-func NewObjectHandler(q *database.Queries, l *log.Logger) *ObjectHandler {
+func NewObjectHandler(q *database.Queries, manager *worker.Manager, s *settings.Store, l *slog.Logger) *ObjectHandler {
 	return &ObjectHandler{
-		queries: q,
-		logger:  l,
+		queries:  q,
+		manager:  manager,
+		settings: s,
+		logger:   l,
 	}
 }
 
 func (h *ObjectHandler) List(w http.ResponseWriter, r *http.Request) {
-	limit := 10
-	if l := r.URL.Query().Get("limit"); l != "" {
-		parsed, err := strconv.Atoi(l)
-		if err == nil && parsed > 0 {
-			limit = parsed
+	page, countMode, err := queryPage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter, err := parseObjectFilter(r, page.Cursor)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	objects, cursors, err := h.queries.SearchObjects(r.Context(), filter, page)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// build pagination with CountSearchObjects
+	count, err := h.queries.CountSearchObjects(r.Context(), filter, countMode)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := map[string]interface{}{
+		"objects": objects,
+		"pagination": paginationResponse(page, countMode, count, cursors),
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseObjectFilter reads the filter and sort query parameters shared by object listings.
+// A cursor pins the sort it was issued for.
+func parseObjectFilter(r *http.Request, cursor *database.Cursor) (database.ObjectFilter, error) {
+	var filter database.ObjectFilter
+	var err error
+	query := r.URL.Query()
+
+	if filter.NeverSynced, err = queryBool(r, "never_synced"); err != nil {
+		return filter, err
+	}
+	if filter.FailedLastAttempt, err = queryBool(r, "failed_last_attempt"); err != nil {
+		return filter, err
+	}
+	if filter.StaleSince, err = queryTime(r, "stale_since"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedAfter, err = queryTime(r, "created_after"); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = queryTime(r, "created_before"); err != nil {
+		return filter, err
+	}
+
+	if cursor != nil {
+		if _, ok := database.ObjectSortFields[cursor.Sort]; !ok {
+			return filter, fmt.Errorf("invalid cursor")
 		}
+		filter.SortBy, filter.SortAsc = cursor.Sort, cursor.Asc
+		return filter, nil
 	}
 
-	offset := 0
-	if o := r.URL.Query().Get("offset"); o != "" {
-		parsed, err := strconv.Atoi(o)
-		if err == nil && parsed >= 0 {
-			offset = parsed
+	filter.SortBy = database.DefaultObjectSort
+	if sortBy := query.Get("sort"); sortBy != "" {
+		if _, ok := database.ObjectSortFields[sortBy]; !ok {
+			return filter, fmt.Errorf("invalid sort %q", sortBy)
 		}
+		filter.SortBy = sortBy
+	}
+	if filter.SortAsc, err = queryOrderAsc(r); err != nil {
+		return filter, err
 	}
+	return filter, nil
+}
 
-	objects, err := h.queries.ListObjects(r.Context(), database.ListObjectsParams{
-		Limit:  int32(limit),
-		Offset: int32(offset),
-	})
+type ObjectDetail struct {
+	database.Object
+	LatestTask    *database.Task   `json:"latest_task"`
+	LastError     *string          `json:"last_error"`
+	LastErrorAt   *time.Time       `json:"last_error_at"`
+	NextRefreshAt *time.Time       `json:"next_refresh_at"`
+	TaskCounts    map[string]int64 `json:"task_counts"`
+}
+
+func (h *ObjectHandler) Get(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
 	if err != nil {
+		http.Error(w, "invalid object id", http.StatusBadRequest)
+		return
+	}
+
+	object, err := h.queries.GetObject(r.Context(), &objectID)
+	if err == sql.ErrNoRows {
+		http.Error(w, "object not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	detail := ObjectDetail{
+		Object:     object,
+		TaskCounts: map[string]int64{},
+	}
+
+	latest, err := h.queries.GetLatestTaskForObject(r.Context(), &objectID)
+	if err != nil && err != sql.ErrNoRows {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err == nil {
+		detail.LatestTask = &latest
+	}
 
-	// build pagination with CountTasks
-	count, err := h.queries.CountObjects(r.Context())
+	lastErr, err := h.queries.GetLatestTaskErrorForObject(r.Context(), &objectID)
+	if err != nil && err != sql.ErrNoRows {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err == nil {
+		detail.LastError = &lastErr.Error.String
+		if lastErr.CompletedAt.Valid {
+			detail.LastErrorAt = &lastErr.CompletedAt.Time
+		}
+	}
+
+	counts, err := h.queries.CountTasksByStatusForObject(r.Context(), &objectID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	pagination := map[string]interface{}{
-		"total": count,
-		"limit": limit,
-		"offset": offset,
+	for _, c := range counts {
+		detail.TaskCounts[c.Status] = c.Count
+	}
+
+	// Never-synced objects are picked up by the next stale scan
+	nextRefresh := time.Now()
+	if object.LastSyncedAt.Valid {
+		nextRefresh = object.LastSyncedAt.Time.Add(h.settings.Current().StaleAfter())
+	}
+	detail.NextRefreshAt = &nextRefresh
+
+	json.NewEncoder(w).Encode(detail)
+}
+
+type EnrichmentVersion struct {
+	database.ObjectEnrichment
+	Diff *worker.SnapshotDiff `json:"diff"`
+}
+
+func (h *ObjectHandler) History(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid object id", http.StatusBadRequest)
+		return
 	}
+
+	snapshots, err := h.queries.ListObjectEnrichments(r.Context(), &objectID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Each version carries the field-level diff against the version before it
+	versions := make([]EnrichmentVersion, 0, len(snapshots))
+	var prev *database.ObjectEnrichment
+	for i := range snapshots {
+		diff, err := worker.DiffSnapshots(prev, &snapshots[i])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		versions = append(versions, EnrichmentVersion{
+			ObjectEnrichment: snapshots[i],
+			Diff:             diff,
+		})
+		prev = &snapshots[i]
+	}
+
 	response := map[string]interface{}{
-		"objects": objects,
-		"pagination": pagination,
+		"object_id": objectID,
+		"versions":  versions,
 	}
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+// RollbackRequest selects the snapshot to restore, either by history version or by the task that produced it
+type RollbackRequest struct {
+	Version *int32     `json:"version"`
+	TaskID  *uuid.UUID `json:"task_id"`
+}
+
+func (h *ObjectHandler) Rollback(w http.ResponseWriter, r *http.Request) {
+	objectID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid object id", http.StatusBadRequest)
+		return
+	}
+
+	var req RollbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if (req.Version == nil) == (req.TaskID == nil) {
+		http.Error(w, "exactly one of version or task_id is required", http.StatusBadRequest)
+		return
+	}
+
+	var target database.ObjectEnrichment
+	if req.Version != nil {
+		target, err = h.queries.GetObjectEnrichmentByVersion(r.Context(), database.GetObjectEnrichmentByVersionParams{
+			ObjectID: &objectID,
+			Version:  *req.Version,
+		})
+	} else {
+		target, err = h.queries.GetObjectEnrichmentByTask(r.Context(), database.GetObjectEnrichmentByTaskParams{
+			ObjectID: &objectID,
+			TaskID:   req.TaskID,
+		})
+	}
+	if err == sql.ErrNoRows {
+		http.Error(w, "snapshot not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result, err := h.manager.Rollback(r.Context(), &target)
+	var rollbackErr *worker.RollbackError
+	switch {
+	case errors.Is(err, worker.ErrRollbackInProgress):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.As(err, &rollbackErr):
+		// The failed task is part of the audit trail, so name it
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error":   rollbackErr.Error(),
+			"task_id": rollbackErr.TaskID,
+		})
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"admin-server/internal/database"
+)
+
+// maxPageLimit caps limit so Limit+1 in the page query cannot overflow int32
+const maxPageLimit = 1000
+
+// queryTime parses an optional RFC3339 query parameter
+func queryTime(r *http.Request, name string) (*time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected RFC3339 timestamp", name)
+	}
+	return &t, nil
+}
+
+// queryBool parses an optional boolean query parameter, defaulting to false
+func queryBool(r *http.Request, name string) (bool, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: expected true or false", name)
+	}
+	return b, nil
+}
+
+// queryPage reads limit, offset, cursor and count shared by paginated listings.
+// Offset requests keep the exact total the admin UI relies on; cursor requests
+// skip counting unless count=exact or count=estimate is asked for.
+func queryPage(r *http.Request) (database.Page, database.CountMode, error) {
+	query := r.URL.Query()
+	page := database.Page{Limit: 10}
+
+	if l := query.Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err == nil && parsed > maxPageLimit {
+			return page, database.CountExact, fmt.Errorf("invalid limit %d: at most %d", parsed, maxPageLimit)
+		}
+		if err == nil && parsed > 0 {
+			page.Limit = int32(parsed)
+		}
+	}
+
+	if o := query.Get("offset"); o != "" {
+		parsed, err := strconv.Atoi(o)
+		if err == nil && parsed > math.MaxInt32 {
+			return page, database.CountExact, fmt.Errorf("invalid offset %d: too large", parsed)
+		}
+		if err == nil && parsed >= 0 {
+			page.Offset = int32(parsed)
+		}
+	}
+
+	countMode := database.CountExact
+	if c := query.Get("cursor"); c != "" {
+		cursor, err := database.DecodeCursor(c)
+		if err != nil {
+			return page, countMode, err
+		}
+		page.Cursor = cursor
+		page.Offset = 0
+		countMode = database.CountNone
+	}
+
+	switch mode := database.CountMode(query.Get("count")); mode {
+	case "":
+	case database.CountExact, database.CountEstimate, database.CountNone:
+		countMode = mode
+	default:
+		return page, countMode, fmt.Errorf("invalid count %q: expected exact, estimate or none", mode)
+	}
+	return page, countMode, nil
+}
+
+func paginationResponse(page database.Page, countMode database.CountMode, total *int64, cursors database.PageCursors) map[string]interface{} {
+	return map[string]interface{}{
+		"total":       total,
+		"count":       countMode,
+		"limit":       page.Limit,
+		"offset":      page.Offset,
+		"next_cursor": cursors.Next,
+		"prev_cursor": cursors.Prev,
+	}
+}
+
+// queryOrderAsc parses the order parameter; listings sort descending by default
+func queryOrderAsc(r *http.Request) (bool, error) {
+	switch order := r.URL.Query().Get("order"); order {
+	case "", "desc":
+		return false, nil
+	case "asc":
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid order %q: expected asc or desc", order)
+	}
+}
+
+// queryDuration parses an optional Go duration query parameter such as 90s or 5m
+func queryDuration(r *http.Request, name string) (*time.Duration, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: expected a duration like 90s or 5m", name)
+	}
+	return &d, nil
+}
+
+// queryInt parses an optional non-negative integer query parameter
+func queryInt(r *http.Request, name string, fallback int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: expected a non-negative integer", name)
+	}
+	return n, nil
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"admin-server/internal/auth"
+	"admin-server/internal/secrets"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SecretHandler shows which secret versions are active and writes to the
+// encrypted database backend. Values are accepted but never returned.
+type SecretHandler struct {
+	store  *secrets.Store
+	logger *slog.Logger
+}
+
+func NewSecretHandler(store *secrets.Store, l *slog.Logger) *SecretHandler {
+	return &SecretHandler{
+		store:  store,
+		logger: l,
+	}
+}
+
+// SetSecretRequest carries the new value. The field is named secret so the
+// audit log redacts it.
+type SetSecretRequest struct {
+	Secret string `json:"secret"`
+}
+
+type SecretVersionResponse struct {
+	Name      string     `json:"name"`
+	Source    string     `json:"source"`
+	Version   string     `json:"version"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	Shadowed  []string   `json:"shadowed,omitempty"`
+}
+
+type SecretProviderResponse struct {
+	Name     string `json:"name"`
+	Writable bool   `json:"writable"`
+	Secrets  int    `json:"secrets"`
+	Error    string `json:"error,omitempty"`
+}
+
+// List returns the active version and source of every secret, and the state of each backend in precedence order
+func (h *SecretHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.writeList(w)
+}
+
+// Reload re-reads every backend now instead of waiting for the next interval
+func (h *SecretHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	if err := h.store.Reload(r.Context()); err != nil {
+		// Failing backends keep their previous values and are reported in the listing
+		h.logger.WarnContext(r.Context(), "reload secrets failed", "error", err)
+	}
+	h.writeList(w)
+}
+
+// Set stores a value in the database backend, where it overrides files and the environment
+func (h *SecretHandler) Set(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if !secrets.ValidName(name) {
+		http.Error(w, "invalid secret name: expected upper case letters, digits and underscores", http.StatusBadRequest)
+		return
+	}
+	var req SetSecretRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Secret == "" {
+		http.Error(w, "secret is required", http.StatusBadRequest)
+		return
+	}
+
+	principal, _ := auth.FromContext(r.Context())
+	err := h.store.Set(r.Context(), name, req.Secret, principal.UserID.String())
+	if errors.Is(err, secrets.ErrReadOnly) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for _, v := range h.store.Versions() {
+		if v.Name == name {
+			h.logger.InfoContext(r.Context(), "secret set", "name", name, "version", v.Version)
+			json.NewEncoder(w).Encode(secretVersionResponse(v))
+			return
+		}
+	}
+	http.Error(w, "secret was stored but is not active", http.StatusInternalServerError)
+}
+
+// Delete removes a value from the database backend. A file or environment value
+// for the same name, if any, becomes active again.
+func (h *SecretHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	deleted, err := h.store.Delete(r.Context(), name)
+	if errors.Is(err, secrets.ErrReadOnly) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "secret not found", http.StatusNotFound)
+		return
+	}
+	h.logger.InfoContext(r.Context(), "secret deleted", "name", name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SecretHandler) writeList(w http.ResponseWriter) {
+	versions := h.store.Versions()
+	response := make([]SecretVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		response = append(response, secretVersionResponse(v))
+	}
+	providers, loadedAt := h.store.Providers()
+	providerResponse := make([]SecretProviderResponse, 0, len(providers))
+	for _, p := range providers {
+		providerResponse = append(providerResponse, SecretProviderResponse{
+			Name:     p.Name,
+			Writable: p.Writable,
+			Secrets:  p.Secrets,
+			Error:    p.Error,
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"secrets":   response,
+		"providers": providerResponse,
+		"loaded_at": loadedAt,
+	})
+}
+
+func secretVersionResponse(v secrets.Version) SecretVersionResponse {
+	response := SecretVersionResponse{
+		Name:     v.Name,
+		Source:   v.Source,
+		Version:  v.Version,
+		Shadowed: v.Shadowed,
+	}
+	// The environment has no modification time
+	if !v.UpdatedAt.IsZero() {
+		response.UpdatedAt = &v.UpdatedAt
+	}
+	return response
+}
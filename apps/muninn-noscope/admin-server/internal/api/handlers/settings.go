@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"admin-server/internal/audit"
+	"admin-server/internal/auth"
+	"admin-server/internal/database"
+	"admin-server/internal/settings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSettingChanges = 100
+	maxSettingChanges     = 1000
+)
+
+type SettingsHandler struct {
+	queries  *database.Queries
+	settings *settings.Store
+	logger   *slog.Logger
+}
+
+func NewSettingsHandler(q *database.Queries, s *settings.Store, l *slog.Logger) *SettingsHandler {
+	return &SettingsHandler{
+		queries:  q,
+		settings: s,
+		logger:   l,
+	}
+}
+
+type UpdateSettingRequest struct {
+	Value json.RawMessage `json:"value"`
+}
+
+type SettingResponse struct {
+	Key         string      `json:"key"`
+	Description string      `json:"description"`
+	Value       interface{} `json:"value"`
+	Default     interface{} `json:"default"`
+	Overridden  bool        `json:"overridden"`
+	UpdatedBy   *uuid.UUID  `json:"updated_by"`
+	UpdatedAt   *time.Time  `json:"updated_at"`
+}
+
+type SettingChangeResponse struct {
+	ID        *uuid.UUID      `json:"id"`
+	Key       string          `json:"key"`
+	OldValue  json.RawMessage `json:"old_value"`
+	NewValue  json.RawMessage `json:"new_value"`
+	ChangedBy *uuid.UUID      `json:"changed_by"`
+	ChangedAt time.Time       `json:"changed_at"`
+}
+
+// List returns every runtime setting with its effective value and configured default
+func (h *SettingsHandler) List(w http.ResponseWriter, r *http.Request) {
+	entries := h.settings.List()
+	response := make([]SettingResponse, 0, len(entries))
+	for _, e := range entries {
+		response = append(response, settingResponse(e))
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"settings": response,
+	})
+}
+
+// Update stores a new value; the worker applies it without a restart
+func (h *SettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	var req UpdateSettingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(req.Value) == 0 {
+		http.Error(w, "value is required", http.StatusBadRequest)
+		return
+	}
+
+	audit.SetTarget(r.Context(), "setting", key)
+	principal, _ := auth.FromContext(r.Context())
+	err := h.settings.Set(r.Context(), key, req.Value, &principal.UserID)
+	if !h.writeError(w, err) {
+		return
+	}
+	h.logger.InfoContext(r.Context(), "setting updated", "key", key)
+	h.writeSetting(w, key)
+}
+
+// Reset removes the stored value so the configured default applies again
+func (h *SettingsHandler) Reset(w http.ResponseWriter, r *http.Request) {
+	key := chi.URLParam(r, "key")
+	audit.SetTarget(r.Context(), "setting", key)
+	principal, _ := auth.FromContext(r.Context())
+	deleted, err := h.settings.Reset(r.Context(), key, &principal.UserID)
+	if !h.writeError(w, err) {
+		return
+	}
+	if deleted {
+		h.logger.InfoContext(r.Context(), "setting reset", "key", key)
+	}
+	h.writeSetting(w, key)
+}
+
+// History lists changes newest first, optionally for one key
+func (h *SettingsHandler) History(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit", defaultSettingChanges)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if limit == 0 || limit > maxSettingChanges {
+		limit = maxSettingChanges
+	}
+	changes, err := h.queries.ListSettingChanges(r.Context(), database.ListSettingChangesParams{
+		Key:      r.URL.Query().Get("key"),
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := make([]SettingChangeResponse, 0, len(changes))
+	for _, c := range changes {
+		response = append(response, SettingChangeResponse{
+			ID:        c.ID,
+			Key:       c.Key,
+			OldValue:  nullRawJSON(c.OldValue.RawMessage, c.OldValue.Valid),
+			NewValue:  nullRawJSON(c.NewValue.RawMessage, c.NewValue.Valid),
+			ChangedBy: c.ChangedBy,
+			ChangedAt: c.ChangedAt,
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"changes": response,
+	})
+}
+
+// writeError maps store errors to responses and reports whether err was nil
+func (h *SettingsHandler) writeError(w http.ResponseWriter, err error) bool {
+	var valueErr *settings.ValueError
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, settings.ErrUnknown):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.As(err, &valueErr):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+	return false
+}
+
+func (h *SettingsHandler) writeSetting(w http.ResponseWriter, key string) {
+	for _, e := range h.settings.List() {
+		if e.Key == key {
+			json.NewEncoder(w).Encode(settingResponse(e))
+			return
+		}
+	}
+}
+
+func settingResponse(e settings.Entry) SettingResponse {
+	response := SettingResponse{
+		Key:         e.Key,
+		Description: e.Description,
+		Value:       e.Value,
+		Default:     e.Default,
+		Overridden:  e.Overridden,
+		UpdatedBy:   e.UpdatedBy,
+	}
+	if e.Overridden {
+		response.UpdatedAt = &e.UpdatedAt
+	}
+	return response
+}
+
+// nullRawJSON renders a nullable JSONB column as its JSON, or null
+func nullRawJSON(raw json.RawMessage, valid bool) json.RawMessage {
+	if !valid {
+		return json.RawMessage("null")
+	}
+	return raw
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"admin-server/internal/database"
+	"admin-server/internal/settings"
+)
+
+// defaultStatsWindow is the time window covered when neither from nor window is given
+const defaultStatsWindow = 24 * time.Hour
+
+type StatsHandler struct {
+	queries  *database.Queries
+	settings *settings.Store
+	logger   *slog.Logger
+}
+
+func NewStatsHandler(q *database.Queries, s *settings.Store, l *slog.Logger) *StatsHandler {
+	return &StatsHandler{
+		queries:  q,
+		settings: s,
+		logger:   l,
+	}
+}
+
+type StatsWindow struct {
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Bucket string    `json:"bucket"`
+}
+
+type TaskCount struct {
+	Status string `json:"status"`
+	Source string `json:"source"`
+	Count  int64  `json:"count"`
+}
+
+type TaskStats struct {
+	Total          int64            `json:"total"`
+	ByStatus       map[string]int64 `json:"by_status"`
+	BySource       map[string]int64 `json:"by_source"`
+	ByStatusSource []TaskCount      `json:"by_status_source"`
+}
+
+type ThroughputBucket struct {
+	Bucket    time.Time `json:"bucket"`
+	Succeeded int64     `json:"succeeded"`
+	Failed    int64     `json:"failed"`
+}
+
+type LatencyStats struct {
+	P50Seconds *float64 `json:"p50_seconds"`
+	P95Seconds *float64 `json:"p95_seconds"`
+}
+
+type BacklogStats struct {
+	Pending                 int64      `json:"pending"`
+	Processing              int64      `json:"processing"`
+	OldestPendingAt         *time.Time `json:"oldest_pending_at"`
+	OldestPendingAgeSeconds *float64   `json:"oldest_pending_age_seconds"`
+}
+
+type ScanStats struct {
+	LastScanAt *time.Time `json:"last_scan_at"`
+	Watermark  *time.Time `json:"watermark"`
+	LagSeconds *float64   `json:"lag_seconds"`
+}
+
+type Stats struct {
+	Window       StatsWindow        `json:"window"`
+	Tasks        TaskStats          `json:"tasks"`
+	Throughput   []ThroughputBucket `json:"throughput"`
+	Finished     int64              `json:"finished"`
+	SuccessRate  *float64           `json:"success_rate"`
+	Latency      LatencyStats       `json:"latency"`
+	Backlog      BacklogStats       `json:"backlog"`
+	StaleObjects int64              `json:"stale_objects"`
+	Scan         ScanStats          `json:"scan"`
+}
+
+// Get reports task and scheduler aggregates. Windowed figures (counts, throughput,
+// success rate, latency) cover [from, to); backlog, stale objects and scan lag are
+// always current. from defaults to to minus window (24h), to defaults to now, and
+// bucket (hour or day) defaults to hour for windows up to two days.
+func (h *StatsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	window, err := parseStatsWindow(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ctx := r.Context()
+	now := time.Now()
+
+	stats := Stats{
+		Window: window,
+		Tasks: TaskStats{
+			ByStatus:       map[string]int64{},
+			BySource:       map[string]int64{},
+			ByStatusSource: []TaskCount{},
+		},
+		Throughput: []ThroughputBucket{},
+	}
+
+	counts, err := h.queries.CountTasksByStatusAndSource(ctx, database.CountTasksByStatusAndSourceParams{
+		WindowStart: window.From,
+		WindowEnd:   window.To,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, c := range counts {
+		stats.Tasks.Total += c.Count
+		stats.Tasks.ByStatus[c.Status] += c.Count
+		stats.Tasks.BySource[c.Source] += c.Count
+		stats.Tasks.ByStatusSource = append(stats.Tasks.ByStatusSource, TaskCount{Status: c.Status, Source: c.Source, Count: c.Count})
+	}
+
+	throughput, err := h.queries.GetTaskThroughput(ctx, database.GetTaskThroughputParams{
+		Bucket:      window.Bucket,
+		WindowStart: window.From,
+		WindowEnd:   window.To,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, t := range throughput {
+		stats.Throughput = append(stats.Throughput, ThroughputBucket(t))
+	}
+
+	outcomes, err := h.queries.GetTaskOutcomeStats(ctx, database.GetTaskOutcomeStatsParams{
+		WindowStart: window.From,
+		WindowEnd:   window.To,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	stats.Finished = outcomes.Finished
+	if outcomes.Finished > 0 {
+		rate := float64(outcomes.Succeeded) / float64(outcomes.Finished)
+		stats.SuccessRate = &rate
+	}
+	stats.Latency.P50Seconds = nullFloat(outcomes.P50Seconds)
+	stats.Latency.P95Seconds = nullFloat(outcomes.P95Seconds)
+
+	backlog, err := h.queries.GetTaskBacklog(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	stats.Backlog.Pending = backlog.Pending
+	stats.Backlog.Processing = backlog.Processing
+	if backlog.OldestPendingAt.Valid {
+		age := now.Sub(backlog.OldestPendingAt.Time).Seconds()
+		stats.Backlog.OldestPendingAt = &backlog.OldestPendingAt.Time
+		stats.Backlog.OldestPendingAgeSeconds = &age
+	}
+
+	stats.StaleObjects, err = h.queries.CountStaleObjects(ctx, sql.NullTime{Time: now.Add(-h.settings.Current().StaleAfter()), Valid: true})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Scan lag is how far the newest object seen by the scanner trails now
+	scan, err := h.queries.GetLatestScanLog(ctx)
+	if err != nil && err != sql.ErrNoRows {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if scan.CreatedAt.Valid {
+		stats.Scan.LastScanAt = &scan.CreatedAt.Time
+	}
+	if scan.Latest.Valid {
+		lag := now.Sub(scan.Latest.Time).Seconds()
+		stats.Scan.Watermark = &scan.Latest.Time
+		stats.Scan.LagSeconds = &lag
+	}
+
+	json.NewEncoder(w).Encode(stats)
+}
+
+func parseStatsWindow(r *http.Request) (StatsWindow, error) {
+	var window StatsWindow
+
+	to, err := queryTime(r, "to")
+	if err != nil {
+		return window, err
+	}
+	window.To = time.Now()
+	if to != nil {
+		window.To = *to
+	}
+
+	from, err := queryTime(r, "from")
+	if err != nil {
+		return window, err
+	}
+	length, err := queryDuration(r, "window")
+	if err != nil {
+		return window, err
+	}
+	switch {
+	case from != nil:
+		window.From = *from
+	case length != nil:
+		window.From = window.To.Add(-*length)
+	default:
+		window.From = window.To.Add(-defaultStatsWindow)
+	}
+	if !window.From.Before(window.To) {
+		return window, fmt.Errorf("invalid window: from must be before to")
+	}
+
+	switch bucket := r.URL.Query().Get("bucket"); bucket {
+	case "":
+		window.Bucket = "hour"
+		if window.To.Sub(window.From) > 48*time.Hour {
+			window.Bucket = "day"
+		}
+	case "hour", "day":
+		window.Bucket = bucket
+	default:
+		return window, fmt.Errorf("invalid bucket %q: expected hour or day", bucket)
+	}
+	return window, nil
+}
+
+func nullFloat(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
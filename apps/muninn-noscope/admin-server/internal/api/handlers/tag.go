@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"admin-server/internal/audit"
+	"admin-server/internal/database"
+	"admin-server/internal/worker"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TagHandler struct {
+	queries *database.Queries
+	logger  *slog.Logger
+}
+
+func NewTagHandler(q *database.Queries, l *slog.Logger) *TagHandler {
+	return &TagHandler{
+		queries: q,
+		logger:  l,
+	}
+}
+
+type UpsertTagAliasRequest struct {
+	Alias string `json:"alias"`
+	Tag   string `json:"tag"`
+}
+
+type BlockTagRequest struct {
+	Tag string `json:"tag"`
+}
+
+func (h *TagHandler) ListAliases(w http.ResponseWriter, r *http.Request) {
+	aliases, err := h.queries.ListTagAliases(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"aliases": aliases,
+	})
+}
+
+func (h *TagHandler) UpsertAlias(w http.ResponseWriter, r *http.Request) {
+	var req UpsertTagAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Store the normalised forms so lookups in the worker are exact matches
+	alias := worker.NormalizeTag(req.Alias)
+	tag := worker.NormalizeTag(req.Tag)
+	if alias == "" || tag == "" {
+		http.Error(w, "alias and tag are required", http.StatusBadRequest)
+		return
+	}
+	if alias == tag {
+		http.Error(w, "alias must differ from tag", http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.queries.UpsertTagAlias(r.Context(), database.UpsertTagAliasParams{
+		Alias: alias,
+		Tag:   tag,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	audit.SetTarget(r.Context(), "tag_alias", saved.Alias)
+	json.NewEncoder(w).Encode(saved)
+}
+
+func (h *TagHandler) DeleteAlias(w http.ResponseWriter, r *http.Request) {
+	deleted, err := h.queries.DeleteTagAlias(r.Context(), worker.NormalizeTag(chi.URLParam(r, "alias")))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if deleted == 0 {
+		http.Error(w, "alias not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *TagHandler) ListBlocklist(w http.ResponseWriter, r *http.Request) {
+	blocked, err := h.queries.ListBlockedTags(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"blocklist": blocked,
+	})
+}
+
+func (h *TagHandler) AddBlocked(w http.ResponseWriter, r *http.Request) {
+	var req BlockTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag := worker.NormalizeTag(req.Tag)
+	if tag == "" {
+		http.Error(w, "tag is required", http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.queries.CreateBlockedTag(r.Context(), tag)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	audit.SetTarget(r.Context(), "tag_blocklist", saved.Tag)
+	json.NewEncoder(w).Encode(saved)
+}
+
+func (h *TagHandler) DeleteBlocked(w http.ResponseWriter, r *http.Request) {
+	deleted, err := h.queries.DeleteBlockedTag(r.Context(), worker.NormalizeTag(chi.URLParam(r, "tag")))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if deleted == 0 {
+		http.Error(w, "tag not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
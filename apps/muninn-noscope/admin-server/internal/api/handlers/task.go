@@ -1,25 +1,29 @@
 package handlers
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
+	"slices"
+	"strings"
 
+	"admin-server/internal/audit"
 	"admin-server/internal/database"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
 type TaskHandler struct {
 	queries *database.Queries
-	logger  *log.Logger
+	logger  *slog.Logger
 	db 		*sql.DB
 }
 
-func NewTaskHandler(q *database.Queries, db *sql.DB, l *log.Logger) *TaskHandler {
+func NewTaskHandler(q *database.Queries, db *sql.DB, l *slog.Logger) *TaskHandler {
 	return &TaskHandler{
 		queries: q,
 		db: db,
@@ -66,6 +70,7 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 	task, err := qtx.CreateTask(r.Context(), database.CreateTaskParams{
 		ObjectID: &req.ObjectID,
 		Input:    req.Input,
+		Source:   "manual",
 	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -77,68 +82,162 @@ func (h *TaskHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	audit.SetTarget(r.Context(), "task", task.ID.String())
 	json.NewEncoder(w).Encode(task)
 }
 
-func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
-	var objectID *uuid.UUID
-	if idStr := r.URL.Query().Get("object_id"); idStr != "" {
-		id, err := uuid.Parse(idStr)
-		if err != nil {
-			http.Error(w, "invalid object_id", http.StatusBadRequest)
-			return
-		}
-		objectID = &id
-	}
+// Cancel fails a pending task before the worker claims it
+func (h *TaskHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.queries.CancelTask, "only pending tasks can be cancelled")
+}
+
+// Requeue runs a failed, completed or unchanged task again. Rollback tasks
+// cannot be requeued, and neither can a task whose object already has one pending.
+func (h *TaskHandler) Requeue(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.queries.RequeueFinishedTask, "task cannot be requeued: it is pending, processing or a rollback, or its object already has a pending task")
+}
 
-	var status string
-	if s := r.URL.Query().Get("status"); s != "" {
-		status = s
+// transition applies a conditional status change, answering 409 with conflict
+// when the task exists but is not in a state the change applies to
+func (h *TaskHandler) transition(w http.ResponseWriter, r *http.Request, update func(context.Context, *uuid.UUID) (database.Task, error), conflict string) {
+	taskID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid task id", http.StatusBadRequest)
+		return
 	}
-	limit := 10
-	if l := r.URL.Query().Get("limit"); l != "" {
-		parsed, err := strconv.Atoi(l)
-		if err == nil && parsed > 0 {
-			limit = parsed
+
+	task, err := update(r.Context(), &taskID)
+	if err == sql.ErrNoRows {
+		if _, err := h.queries.GetTask(r.Context(), &taskID); err == sql.ErrNoRows {
+			http.Error(w, "task not found", http.StatusNotFound)
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		} else {
+			http.Error(w, conflict, http.StatusConflict)
 		}
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
 
-	offset := 0
-	if o := r.URL.Query().Get("offset"); o != "" {
-		parsed, err := strconv.Atoi(o)
-		if err == nil && parsed >= 0 {
-			offset = parsed
-		}
+	h.logger.InfoContext(r.Context(), "task status changed", "task_id", task.ID, "status", task.Status)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(task)
+}
+
+func (h *TaskHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, countMode, err := queryPage(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	listTaskParams := database.ListTasksParams{
-		Column1: objectID,
-		Column2: status,  // This is the issue - empty string is not NULL
-		Limit:   int32(limit),
-		Offset:  int32(offset),
+
+	filter, err := parseTaskFilter(r, page.Cursor)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-	tasks, err := h.queries.ListTasks(r.Context(), listTaskParams)
+
+	tasks, cursors, err := h.queries.SearchTasks(r.Context(), filter, page)
 	if err != nil {
-		fmt.Println(err)
+		h.logger.ErrorContext(r.Context(), "search tasks failed", "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	// build pagination with CountTasks
-	count, err := h.queries.CountTasks(r.Context(), database.CountTasksParams{
-		Column1: objectID,
-		Column2: status,
-	})
+	// build pagination with CountSearchTasks
+	count, err := h.queries.CountSearchTasks(r.Context(), filter, countMode)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	pagination := map[string]interface{}{
-		"total": count,
-		"limit": limit,
-		"offset": offset,
-	}
 	response := map[string]interface{}{
 		"tasks": tasks,
-		"pagination": pagination,
+		"pagination": paginationResponse(page, countMode, count, cursors),
 	}
 	json.NewEncoder(w).Encode(response)
-}
\ No newline at end of file
+}
+
+// parseTaskFilter reads the filter and sort query parameters shared by task listings.
+// status may be repeated or comma-separated, json may be repeated (see database.ParseJSONCondition),
+// and a cursor pins the sort it was issued for.
+func parseTaskFilter(r *http.Request, cursor *database.Cursor) (database.TaskFilter, error) {
+	var filter database.TaskFilter
+	var err error
+	query := r.URL.Query()
+
+	if idStr := query.Get("object_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid object_id")
+		}
+		filter.ObjectID = &id
+	}
+
+	for _, s := range query["status"] {
+		for _, status := range strings.Split(s, ",") {
+			status = strings.TrimSpace(status)
+			if status == "" {
+				continue
+			}
+			if !slices.Contains(database.TaskStatuses, status) {
+				return filter, fmt.Errorf("invalid status %q", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	ranges := []struct {
+		name string
+		dest *database.TimeRange
+	}{
+		{"created", &filter.Created},
+		{"started", &filter.Started},
+		{"completed", &filter.Completed},
+	}
+	for _, tr := range ranges {
+		if tr.dest.After, err = queryTime(r, tr.name+"_after"); err != nil {
+			return filter, err
+		}
+		if tr.dest.Before, err = queryTime(r, tr.name+"_before"); err != nil {
+			return filter, err
+		}
+	}
+
+	filter.ErrorSearch = strings.TrimSpace(query.Get("error_search"))
+
+	for _, c := range query["json"] {
+		cond, err := database.ParseJSONCondition(c)
+		if err != nil {
+			return filter, err
+		}
+		filter.JSON = append(filter.JSON, cond)
+	}
+
+	if filter.MinDuration, err = queryDuration(r, "min_duration"); err != nil {
+		return filter, err
+	}
+	if filter.MaxDuration, err = queryDuration(r, "max_duration"); err != nil {
+		return filter, err
+	}
+
+	if cursor != nil {
+		if _, ok := database.TaskSortFields[cursor.Sort]; !ok {
+			return filter, fmt.Errorf("invalid cursor")
+		}
+		filter.SortBy, filter.SortAsc = cursor.Sort, cursor.Asc
+		return filter, nil
+	}
+
+	filter.SortBy = database.DefaultTaskSort
+	if sortBy := query.Get("sort"); sortBy != "" {
+		if _, ok := database.TaskSortFields[sortBy]; !ok {
+			return filter, fmt.Errorf("invalid sort %q", sortBy)
+		}
+		filter.SortBy = sortBy
+	}
+	if filter.SortAsc, err = queryOrderAsc(r); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
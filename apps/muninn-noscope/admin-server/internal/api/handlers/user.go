@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"admin-server/internal/audit"
+	"admin-server/internal/auth"
+	"admin-server/internal/database"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type UserHandler struct {
+	queries *database.Queries
+	logger  *slog.Logger
+}
+
+func NewUserHandler(q *database.Queries, l *slog.Logger) *UserHandler {
+	return &UserHandler{
+		queries: q,
+		logger:  l,
+	}
+}
+
+type CreateUserRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role"`
+}
+
+func (h *UserHandler) List(w http.ResponseWriter, r *http.Request) {
+	users, err := h.queries.ListUsers(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := make([]UserResponse, 0, len(users))
+	for _, u := range users {
+		response = append(response, userResponse(u))
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"users": response,
+	})
+}
+
+// Create adds a user; role defaults to viewer
+func (h *UserHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	email := auth.NormalizeEmail(req.Email)
+	if email == "" {
+		http.Error(w, "email is required", http.StatusBadRequest)
+		return
+	}
+	role := auth.RoleViewer
+	if req.Role != "" {
+		var err error
+		if role, err = auth.ParseRole(req.Role); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.queries.CreateUser(r.Context(), database.CreateUserParams{
+		Email:        email,
+		PasswordHash: hash,
+		Role:         string(role),
+	})
+	if database.IsUniqueViolation(err) {
+		http.Error(w, "a user with that email already exists", http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	audit.SetTarget(r.Context(), "user", user.ID.String())
+	h.logger.InfoContext(r.Context(), "user created", "target_user_id", user.ID, "role", role)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(userResponse(user))
+}
+
+// UpdateRole assigns a role. Admins cannot change their own role, so there is
+// always at least one admin left.
+func (h *UserHandler) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "invalid user id", http.StatusBadRequest)
+		return
+	}
+	var req UpdateRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	role, err := auth.ParseRole(req.Role)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if principal, _ := auth.FromContext(r.Context()); principal.UserID == id {
+		http.Error(w, "cannot change your own role", http.StatusForbidden)
+		return
+	}
+
+	user, err := h.queries.UpdateUserRole(r.Context(), database.UpdateUserRoleParams{
+		ID:   &id,
+		Role: string(role),
+	})
+	if err == sql.ErrNoRows {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.logger.InfoContext(r.Context(), "user role changed", "target_user_id", user.ID, "role", role)
+	json.NewEncoder(w).Encode(userResponse(user))
+}
@@ -2,43 +2,222 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"slices"
+	"sync"
+	"time"
 
+	"admin-server/internal/auth"
+	"admin-server/internal/settings"
 	"admin-server/internal/worker"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
+// WorkerControlHandler changes the desired worker state, which is stored in the
+// settings table. Every process applies it to the components it runs, so an api
+// process controls the worker and scheduler processes too. This process applies
+// it before answering; others pick it up within the settings reload interval.
 type WorkerControlHandler struct {
-	manager *worker.Manager
+	manager      *worker.Manager
+	settings     *settings.Store
+	drainTimeout time.Duration
+	logger       *slog.Logger
+	// mu serialises read-modify-write changes to the paused task list
+	mu sync.Mutex
 }
 
-func NewWorkerControlHandler(manager *worker.Manager) *WorkerControlHandler {
+func NewWorkerControlHandler(manager *worker.Manager, settingsStore *settings.Store, drainTimeout time.Duration, l *slog.Logger) *WorkerControlHandler {
 	return &WorkerControlHandler{
-			manager: manager,
+		manager:      manager,
+		settings:     settingsStore,
+		drainTimeout: drainTimeout,
+		logger:       l,
 	}
 }
 
+// HandleStart sets the processor and the scheduler running. Both are stored in
+// one change, so a failure leaves neither changed.
 func (h *WorkerControlHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
-	if err := h.manager.Start(); err != nil {
-		http.Error(w, err.Error(), http.StatusConflict)
+	if !h.setMany(w, r, map[string]interface{}{
+		settings.Processor: settings.ProcessorState{State: settings.StateRunning},
+		settings.Scheduler: settings.StateRunning,
+	}) {
 		return
 	}
-
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "started"})
+	h.writeStatus(w, r, http.StatusOK)
 }
 
+// HandleStop stops the processor and the scheduler. Tasks in flight are
+// cancelled immediately and requeued.
 func (h *WorkerControlHandler) HandleStop(w http.ResponseWriter, r *http.Request) {
-	if err := h.manager.Stop(); err != nil {
-		http.Error(w, err.Error(), http.StatusConflict)
+	if !h.setMany(w, r, map[string]interface{}{
+		settings.Processor: settings.ProcessorState{State: settings.StateStopped},
+		settings.Scheduler: settings.StateStopped,
+	}) {
+		return
+	}
+	h.writeStatus(w, r, http.StatusOK)
+}
+
+// HandlePause stops claiming new tasks and lets the ones in flight finish
+func (h *WorkerControlHandler) HandlePause(w http.ResponseWriter, r *http.Request) {
+	if !h.setProcessor(w, r, settings.ProcessorState{State: settings.StatePaused}) {
 		return
 	}
+	h.writeStatus(w, r, http.StatusOK)
+}
 
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "stopped"})
+func (h *WorkerControlHandler) HandleResume(w http.ResponseWriter, r *http.Request) {
+	h.HandleStartProcessor(w, r)
+}
+
+// HandleDrain stops the processors once the tasks in flight finish, cancelling
+// any still running after ?timeout= (a duration such as 90s, default from config).
+// It returns 202 straight away; GET /api/worker/status shows the progress.
+func (h *WorkerControlHandler) HandleDrain(w http.ResponseWriter, r *http.Request) {
+	timeout := h.drainTimeout
+	if v := r.URL.Query().Get("timeout"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			http.Error(w, fmt.Sprintf("invalid timeout %q: expected a positive duration such as 90s", v), http.StatusBadRequest)
+			return
+		}
+		timeout = parsed
+	}
+	deadline := time.Now().Add(timeout)
+	if !h.setProcessor(w, r, settings.ProcessorState{State: settings.StateStopped, DrainUntil: &deadline}) {
+		return
+	}
+	h.writeStatus(w, r, http.StatusAccepted)
 }
 
 func (h *WorkerControlHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
 	metrics := h.manager.GetMetrics()
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(metrics)
-}
\ No newline at end of file
+}
+
+// HandleStatus reports this process's processor, scheduler and scheduled tasks
+// next to the desired state
+func (h *WorkerControlHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(h.manager.Status())
+}
+
+func (h *WorkerControlHandler) HandleStartProcessor(w http.ResponseWriter, r *http.Request) {
+	if !h.setProcessor(w, r, settings.ProcessorState{State: settings.StateRunning}) {
+		return
+	}
+	h.writeStatus(w, r, http.StatusOK)
+}
+
+// HandleStopProcessor cancels the tasks in flight immediately; use drain to let them finish
+func (h *WorkerControlHandler) HandleStopProcessor(w http.ResponseWriter, r *http.Request) {
+	if !h.setProcessor(w, r, settings.ProcessorState{State: settings.StateStopped}) {
+		return
+	}
+	h.writeStatus(w, r, http.StatusOK)
+}
+
+func (h *WorkerControlHandler) HandleStartScheduler(w http.ResponseWriter, r *http.Request) {
+	if !h.setScheduler(w, r, settings.StateRunning) {
+		return
+	}
+	h.writeStatus(w, r, http.StatusOK)
+}
+
+// HandleStopScheduler cancels scheduled runs in progress; the processor is unaffected
+func (h *WorkerControlHandler) HandleStopScheduler(w http.ResponseWriter, r *http.Request) {
+	if !h.setScheduler(w, r, settings.StateStopped) {
+		return
+	}
+	h.writeStatus(w, r, http.StatusOK)
+}
+
+func (h *WorkerControlHandler) HandlePauseScheduledTask(w http.ResponseWriter, r *http.Request) {
+	h.setScheduledTaskPaused(w, r, true)
+}
+
+func (h *WorkerControlHandler) HandleResumeScheduledTask(w http.ResponseWriter, r *http.Request) {
+	h.setScheduledTaskPaused(w, r, false)
+}
+
+func (h *WorkerControlHandler) setScheduledTaskPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	name := chi.URLParam(r, "name")
+	// Every process registers the same scheduled tasks
+	known := slices.ContainsFunc(h.manager.Status().Scheduler.Tasks, func(t worker.ScheduledTaskStatus) bool {
+		return t.Name == name
+	})
+	if !known {
+		http.Error(w, fmt.Sprintf("%v %q", worker.ErrUnknownTask, name), http.StatusNotFound)
+		return
+	}
+
+	h.mu.Lock()
+	names := h.settings.Current().PausedScheduledTasks
+	if paused && !slices.Contains(names, name) {
+		names = append(names, name)
+	} else if !paused {
+		names = slices.DeleteFunc(names, func(n string) bool { return n == name })
+	}
+	ok := h.set(w, r, settings.PausedScheduledTasks, names)
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+	h.writeStatus(w, r, http.StatusOK)
+}
+
+func (h *WorkerControlHandler) setProcessor(w http.ResponseWriter, r *http.Request, state settings.ProcessorState) bool {
+	return h.set(w, r, settings.Processor, state)
+}
+
+func (h *WorkerControlHandler) setScheduler(w http.ResponseWriter, r *http.Request, state string) bool {
+	return h.set(w, r, settings.Scheduler, state)
+}
+
+// set stores a desired state setting, answering with an error when it cannot
+func (h *WorkerControlHandler) set(w http.ResponseWriter, r *http.Request, key string, value interface{}) bool {
+	return h.setMany(w, r, map[string]interface{}{key: value})
+}
+
+// setMany stores several desired state settings in one change
+func (h *WorkerControlHandler) setMany(w http.ResponseWriter, r *http.Request, values map[string]interface{}) bool {
+	raw := make(map[string]json.RawMessage, len(values))
+	for key, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return false
+		}
+		raw[key] = encoded
+	}
+	// API keys are not users; the audit log records which key made the change
+	var changedBy *uuid.UUID
+	if principal, _ := auth.FromContext(r.Context()); !principal.IsAPIKey() {
+		changedBy = &principal.UserID
+	}
+	if err := h.settings.SetMany(r.Context(), raw, changedBy); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	for key, value := range raw {
+		h.logger.InfoContext(r.Context(), "desired worker state changed", "key", key, "value", value)
+	}
+	return true
+}
+
+// writeStatus applies the desired state to this process and answers with the
+// result. A failure to apply it, e.g. a missing secret, shows as apply_error;
+// the change itself is saved and retried.
+func (h *WorkerControlHandler) writeStatus(w http.ResponseWriter, r *http.Request, code int) {
+	if err := h.manager.Reconcile(); err != nil {
+		h.logger.WarnContext(r.Context(), "apply desired worker state failed", "error", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(h.manager.Status())
+}
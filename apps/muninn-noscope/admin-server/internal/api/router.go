@@ -2,12 +2,21 @@ package api
 
 import (
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
-	"os"
+	"strings"
+	"time"
 
+	"admin-server/internal/api/config"
 	"admin-server/internal/api/handlers"
+	"admin-server/internal/audit"
+	"admin-server/internal/auth"
 	"admin-server/internal/database"
+	"admin-server/internal/logging"
+	"admin-server/internal/metrics"
+	"admin-server/internal/secrets"
+	"admin-server/internal/settings"
 	"admin-server/internal/worker"
 
 	"github.com/go-chi/chi/v5"
@@ -15,58 +24,301 @@ import (
 	"github.com/go-chi/cors"
 )
 
-func NewRouter(queries *database.Queries, logger *log.Logger, db *sql.DB, workerMgr *worker.Manager) chi.Router {
+// auditActions names the state-changing routes recorded in audit_events. The part
+// before the dot becomes the event's target type.
+var auditActions = map[string]string{
+	"POST /login":                                    "auth.login",
+	"POST /refresh":                                  "auth.refresh",
+	"POST /logout":                                   "auth.logout",
+	"POST /tasks":                                    "task.create",
+	"POST /tasks/{id}/cancel":                        "task.cancel",
+	"POST /tasks/{id}/requeue":                       "task.requeue",
+	"POST /objects/{id}/rollback":                    "object.rollback",
+	"POST /api/worker/start":                         "worker.start",
+	"POST /api/worker/stop":                          "worker.stop",
+	"POST /api/worker/pause":                         "worker.pause",
+	"POST /api/worker/resume":                        "worker.resume",
+	"POST /api/worker/drain":                         "worker.drain",
+	"POST /api/worker/processor/start":               "worker.processor_start",
+	"POST /api/worker/processor/stop":                "worker.processor_stop",
+	"POST /api/worker/scheduler/start":               "worker.scheduler_start",
+	"POST /api/worker/scheduler/stop":                "worker.scheduler_stop",
+	"POST /api/worker/scheduler/tasks/{name}/pause":  "worker.scheduled_task_pause",
+	"POST /api/worker/scheduler/tasks/{name}/resume": "worker.scheduled_task_resume",
+	"PUT /tags/aliases":                              "tag_alias.upsert",
+	"DELETE /tags/aliases/{alias}":                   "tag_alias.delete",
+	"POST /tags/blocklist":                           "tag_blocklist.add",
+	"DELETE /tags/blocklist/{tag}":                   "tag_blocklist.delete",
+	"POST /users":                                    "user.create",
+	"PUT /users/{id}/role":                           "user.update_role",
+	"POST /api-keys":                                 "api_key.create",
+	"POST /api-keys/{id}/rotate":                     "api_key.rotate",
+	"DELETE /api-keys/{id}":                          "api_key.revoke",
+	"POST /secrets/reload":                           "secret.reload",
+	"PUT /secrets/{name}":                            "secret.set",
+	"DELETE /secrets/{name}":                         "secret.delete",
+	"PUT /settings/{key}":                            "setting.update",
+	"DELETE /settings/{key}":                         "setting.reset",
+}
+
+func NewRouter(queries *database.Queries, logger *slog.Logger, db *sql.DB, workerMgr *worker.Manager, secretStore *secrets.Store, settingsStore *settings.Store, cfg *config.Config) chi.Router {
 	r := chi.NewRouter()
 
 	// Middleware
-	r.Use(middleware.Logger)
+	r.Use(logging.Middleware(logger))
+	r.Use(metrics.Middleware)
 	r.Use(middleware.Recoverer)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"*"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "x-control-key", "x-user-secret"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: true,
 		MaxAge:          300,
 	}))
+	r.Use(audit.Middleware(queries, logger, auditActions))
+
+	metrics.RegisterQueueCollector(queries, logger)
+	issuer := auth.NewIssuer(cfg.Auth.JWTSecret, time.Duration(cfg.Auth.AccessTokenTTL))
 
 	// Initialize handlers
 	taskHandler := handlers.NewTaskHandler(queries, db, logger)
-	objectHandler := handlers.NewObjectHandler(queries, logger)
-	workerCtrl := handlers.NewWorkerControlHandler(workerMgr)
-	authCtrl := handlers.NewAuthHandler(queries, logger)
-
-	// Routes
-	r.Post("/tasks", taskHandler.Create)
-	r.Get("/tasks", taskHandler.List)
-	r.Get("/objects", objectHandler.List)
-	r.Post("/login", authCtrl.Login)
-
-	r.Get("/stats", handlers.HealthCheck(queries))
-	
-	r.Route("/api/worker", func(r chi.Router) {
-		r.Use(authenticateWorkerControl)
-		r.Post("/start", workerCtrl.HandleStart)
-		r.Post("/stop", workerCtrl.HandleStop)
-		r.Get("/metrics", workerCtrl.HandleMetrics)
-	})
-
-	return r
+	objectHandler := handlers.NewObjectHandler(queries, workerMgr, settingsStore, logger)
+	workerCtrl := handlers.NewWorkerControlHandler(workerMgr, settingsStore, time.Duration(cfg.Worker.DrainTimeout), logger)
+	authCtrl := handlers.NewAuthHandler(queries, issuer, time.Duration(cfg.Auth.RefreshTokenTTL), logger)
+	tagHandler := handlers.NewTagHandler(queries, logger)
+	exportHandler := handlers.NewExportHandler(queries, db, logger)
+	statsHandler := handlers.NewStatsHandler(queries, settingsStore, logger)
+	logHandler := handlers.NewLogHandler(cfg.Log.Directory, logger)
+	muninnHandler := handlers.NewMuninnHandler(workerMgr.Muninn(), secretStore, logger)
+	userHandler := handlers.NewUserHandler(queries, logger)
+	apiKeyHandler := handlers.NewAPIKeyHandler(queries, logger)
+	auditHandler := handlers.NewAuditHandler(queries, logger)
+	secretHandler := handlers.NewSecretHandler(secretStore, logger)
+	configHandler := handlers.NewConfigHandler(cfg)
+	settingsHandler := handlers.NewSettingsHandler(queries, settingsStore, logger)
 
-}
+	// Public routes
+	if cfg.ServesAPI() {
+		r.Post("/login", authCtrl.Login)
+		r.Post("/refresh", authCtrl.Refresh)
+	}
+	r.Get("/health", handlers.HealthCheck(queries, cfg.Health.CodeFolder))
+	if cfg.Metrics.Public {
+		r.Method(http.MethodGet, "/metrics", metrics.Handler())
+	}
 
+	// Everything else needs an access token or an API key. Each route names the
+	// least role a user needs and the scope an API key needs; routes without a
+	// scope are for users only.
+	r.Group(func(r chi.Router) {
+		r.Use(authenticate(issuer, queries, logger))
 
-func authenticateWorkerControl(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		key := r.Header.Get("X-Control-Key")
-		if key == "" {
-			http.Error(w, "missing control key", http.StatusUnauthorized)
-			return
+		if !cfg.Metrics.Public {
+			r.With(authorize(auth.RoleViewer, auth.ScopeMetricsRead)).Method(http.MethodGet, "/metrics", metrics.Handler())
 		}
-		if key != os.Getenv("CONTROL_SECRET_KEY") {
-			http.Error(w, "invalid control key", http.StatusUnauthorized)
+
+		// Worker control is served in every mode. Changes are stored as the
+		// desired state, which every process applies to its own components.
+		r.Group(func(r chi.Router) {
+			r.Use(authorize(auth.RoleViewer, auth.ScopeTasksRead))
+			r.Get("/api/worker/metrics", workerCtrl.HandleMetrics)
+			r.Get("/api/worker/status", workerCtrl.HandleStatus)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authorize(auth.RoleAdmin, auth.ScopeWorkerControl))
+			r.Post("/api/worker/start", workerCtrl.HandleStart)
+			r.Post("/api/worker/stop", workerCtrl.HandleStop)
+			r.Post("/api/worker/pause", workerCtrl.HandlePause)
+			r.Post("/api/worker/resume", workerCtrl.HandleResume)
+			r.Post("/api/worker/drain", workerCtrl.HandleDrain)
+			r.Post("/api/worker/processor/start", workerCtrl.HandleStartProcessor)
+			r.Post("/api/worker/processor/stop", workerCtrl.HandleStopProcessor)
+			r.Post("/api/worker/scheduler/start", workerCtrl.HandleStartScheduler)
+			r.Post("/api/worker/scheduler/stop", workerCtrl.HandleStopScheduler)
+			r.Post("/api/worker/scheduler/tasks/{name}/pause", workerCtrl.HandlePauseScheduledTask)
+			r.Post("/api/worker/scheduler/tasks/{name}/resume", workerCtrl.HandleResumeScheduledTask)
+		})
+
+		if !cfg.ServesAPI() {
 			return
 		}
-		next.ServeHTTP(w, r)
+
+		r.Group(func(r chi.Router) {
+			r.Use(authorize(auth.RoleViewer, ""))
+			r.Post("/logout", authCtrl.Logout)
+			r.Get("/me", authCtrl.Me)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authorize(auth.RoleViewer, auth.ScopeTasksRead))
+			r.Get("/tasks", taskHandler.List)
+			r.Get("/tasks/export", exportHandler.Tasks)
+			r.Get("/stats", statsHandler.Get)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authorize(auth.RoleViewer, auth.ScopeObjectsRead))
+			r.Get("/objects", objectHandler.List)
+			r.Get("/objects/export", exportHandler.Objects)
+			r.Get("/objects/{id}", objectHandler.Get)
+			r.Get("/objects/{id}/history", objectHandler.History)
+			r.Get("/tags/aliases", tagHandler.ListAliases)
+			r.Get("/tags/blocklist", tagHandler.ListBlocklist)
+			r.Get("/muninn/objects", muninnHandler.SearchObjects)
+			r.Get("/muninn/objects/{id}", muninnHandler.GetObject)
+		})
+
+		r.Group(func(r chi.Router) {
+			r.Use(authorize(auth.RoleOperator, auth.ScopeTasksWrite))
+			r.Post("/tasks", taskHandler.Create)
+			r.Post("/tasks/{id}/cancel", taskHandler.Cancel)
+			r.Post("/tasks/{id}/requeue", taskHandler.Requeue)
+		})
+		r.With(authorize(auth.RoleOperator, auth.ScopeObjectsWrite)).Post("/objects/{id}/rollback", objectHandler.Rollback)
+
+		r.Group(func(r chi.Router) {
+			r.Use(authorize(auth.RoleAdmin, ""))
+			r.Put("/tags/aliases", tagHandler.UpsertAlias)
+			r.Delete("/tags/aliases/{alias}", tagHandler.DeleteAlias)
+			r.Post("/tags/blocklist", tagHandler.AddBlocked)
+			r.Delete("/tags/blocklist/{tag}", tagHandler.DeleteBlocked)
+
+			r.Get("/logs", logHandler.List)
+			r.Get("/logs/tail", logHandler.Tail)
+			r.Get("/logs/{name}", logHandler.Get)
+
+			r.Get("/users", userHandler.List)
+			r.Post("/users", userHandler.Create)
+			r.Put("/users/{id}/role", userHandler.UpdateRole)
+
+			r.Get("/api-keys", apiKeyHandler.List)
+			r.Post("/api-keys", apiKeyHandler.Create)
+			r.Post("/api-keys/{id}/rotate", apiKeyHandler.Rotate)
+			r.Delete("/api-keys/{id}", apiKeyHandler.Revoke)
+
+			r.Get("/audit", auditHandler.List)
+			r.Get("/config", configHandler.Get)
+
+			r.Get("/settings", settingsHandler.List)
+			r.Get("/settings/history", settingsHandler.History)
+			r.Put("/settings/{key}", settingsHandler.Update)
+			r.Delete("/settings/{key}", settingsHandler.Reset)
+
+			r.Get("/secrets", secretHandler.List)
+			r.Post("/secrets/reload", secretHandler.Reload)
+			r.Put("/secrets/{name}", secretHandler.Set)
+			r.Delete("/secrets/{name}", secretHandler.Delete)
+		})
 	})
-}
\ No newline at end of file
+
+	return r
+
+}
+
+
+// apiKeyTouchInterval is how stale an API key's last_used_at may get
+const apiKeyTouchInterval = time.Minute
+
+// queryTokenRoutes are the EventSource routes that take ?access_token=
+var queryTokenRoutes = map[string]bool{
+	"GET /logs/tail": true,
+}
+
+// authenticate accepts a Bearer access token whose session is still active, or an
+// API key in the X-API-Key header. Browsers cannot set headers on an EventSource,
+// so the streaming routes in queryTokenRoutes may pass the access token as
+// ?access_token= instead; elsewhere it would leak into logs and history.
+func authenticate(issuer *auth.Issuer, queries *database.Queries, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get("X-API-Key"); key != "" {
+				apiKey, err := queries.GetActiveAPIKey(r.Context(), auth.HashAPIKey(key))
+				if err == sql.ErrNoRows {
+					http.Error(w, "invalid, expired or revoked api key", http.StatusUnauthorized)
+					return
+				} else if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				// last_used_at only needs minute precision, so most requests skip the write
+				if !apiKey.LastUsedAt.Valid || time.Since(apiKey.LastUsedAt.Time) > apiKeyTouchInterval {
+					if err := queries.TouchAPIKey(r.Context(), apiKey.ID); err != nil {
+						logger.WarnContext(r.Context(), "record api key use failed", "api_key_id", apiKey.ID, "error", err)
+					}
+				}
+				// Scopes were validated when the key was created
+				scopes := make([]auth.Scope, len(apiKey.Scopes))
+				for i, s := range apiKey.Scopes {
+					scopes[i] = auth.Scope(s)
+				}
+
+				audit.SetActor(r.Context(), audit.ActorAPIKey, *apiKey.ID, apiKey.Name)
+				ctx := auth.WithPrincipal(r.Context(), auth.Principal{APIKeyID: *apiKey.ID, Scopes: scopes})
+				ctx = logging.With(ctx, "api_key_id", apiKey.ID.String(), "api_key_name", apiKey.Name)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok && queryTokenRoutes[r.Method+" "+r.URL.Path] {
+				token = r.URL.Query().Get("access_token")
+			}
+			if token == "" {
+				http.Error(w, "missing access token", http.StatusUnauthorized)
+				return
+			}
+			userID, sessionID, err := issuer.Parse(token)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			// Checking the session makes logout effective before the token expires, and
+			// reading the role here means role changes apply to the next request
+			user, err := queries.GetActiveSessionUser(r.Context(), &sessionID)
+			if err == sql.ErrNoRows || (err == nil && (*user.ID != userID || user.Disabled)) {
+				http.Error(w, "session expired or revoked", http.StatusUnauthorized)
+				return
+			} else if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			role, err := auth.ParseRole(user.Role)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			audit.SetActor(r.Context(), audit.ActorUser, userID, user.Email)
+			ctx := auth.WithPrincipal(r.Context(), auth.Principal{UserID: userID, SessionID: sessionID, Role: role})
+			ctx = logging.With(ctx, "user_id", userID.String())
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authorize admits users whose role includes role and API keys granted scope. It runs after authenticate.
+func authorize(role auth.Role, scope auth.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				http.Error(w, "unauthenticated", http.StatusUnauthorized)
+				return
+			}
+			if !principal.Can(role, scope) {
+				switch {
+				case !principal.IsAPIKey():
+					http.Error(w, fmt.Sprintf("requires %s role", role), http.StatusForbidden)
+				case scope == "":
+					http.Error(w, "not available to api keys", http.StatusForbidden)
+				default:
+					http.Error(w, fmt.Sprintf("api key lacks %s scope", scope), http.StatusForbidden)
+				}
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
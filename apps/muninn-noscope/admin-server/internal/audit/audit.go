@@ -0,0 +1,55 @@
+// Package audit records administrative actions in the append-only audit_events
+// table. Middleware writes one event per state-changing request; authentication
+// and handlers fill in the actor and target as they learn them.
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Actor types stored in audit_events.actor_type
+const (
+	ActorAnonymous = "anonymous"
+	ActorUser      = "user"
+	ActorAPIKey    = "api_key"
+)
+
+// Results stored in audit_events.result
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+	ResultDenied  = "denied"
+)
+
+// entry collects what is known about a request while it is handled
+type entry struct {
+	actorType  string
+	actorID    *uuid.UUID
+	actorName  string
+	targetType string
+	targetID   string
+}
+
+type entryKey struct{}
+
+func entryFrom(ctx context.Context) *entry {
+	e, _ := ctx.Value(entryKey{}).(*entry)
+	return e
+}
+
+// SetActor records who made the request. It does nothing outside audited requests.
+func SetActor(ctx context.Context, actorType string, id uuid.UUID, name string) {
+	if e := entryFrom(ctx); e != nil {
+		e.actorType, e.actorID, e.actorName = actorType, &id, name
+	}
+}
+
+// SetTarget records what the request acted on, overriding the target taken from
+// the route's URL parameters. Handlers call it when they create something new.
+func SetTarget(ctx context.Context, targetType, targetID string) {
+	if e := entryFrom(ctx); e != nil {
+		e.targetType, e.targetID = targetType, targetID
+	}
+}
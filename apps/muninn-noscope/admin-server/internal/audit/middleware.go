@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"admin-server/internal/database"
+	"admin-server/internal/logging"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// maxErrorBytes is how much of an error response is kept in audit_events.error
+const maxErrorBytes = 512
+
+// URL parameters used as the target when a handler does not set one
+var targetParams = []string{"id", "alias", "tag", "name"}
+
+// Middleware records every request that can change state (anything but GET, HEAD
+// and OPTIONS) once it completes. actions maps "METHOD /route/pattern" to an action
+// name such as task.create; unmapped routes use the method and pattern. Requests
+// that match no route are not recorded.
+func Middleware(queries *database.Queries, logger *slog.Logger, actions map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			payload := summarizeBody(r)
+			e := &entry{actorType: ActorAnonymous}
+			ctx := context.WithValue(r.Context(), entryKey{}, e)
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			body := &limitedBuffer{limit: maxErrorBytes}
+			ww.Tee(body)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil || rctx.RoutePattern() == "" {
+				return
+			}
+			route := rctx.RoutePattern()
+			action := actions[r.Method+" "+route]
+			if action == "" {
+				action = r.Method + " " + route
+			}
+
+			if e.targetID == "" {
+				for _, name := range targetParams {
+					if v := rctx.URLParam(name); v != "" {
+						e.targetID = v
+						break
+					}
+				}
+			}
+			if e.targetType == "" {
+				if prefix, _, ok := strings.Cut(action, "."); ok {
+					e.targetType = prefix
+				}
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			result := ResultSuccess
+			switch {
+			case status == http.StatusUnauthorized || status == http.StatusForbidden:
+				result = ResultDenied
+			case status >= http.StatusBadRequest:
+				result = ResultFailure
+			}
+			var errorText string
+			if status >= http.StatusBadRequest {
+				errorText = strings.TrimSpace(body.String())
+			}
+
+			ip := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+				ip = host
+			}
+
+			// The event is written even if the client has already gone away
+			err := queries.CreateAuditEvent(context.WithoutCancel(ctx), database.CreateAuditEventParams{
+				ActorType:    e.actorType,
+				ActorID:      e.actorID,
+				ActorName:    nullString(e.actorName),
+				Action:       action,
+				TargetType:   nullString(e.targetType),
+				TargetID:     nullString(e.targetID),
+				RequestID:    nullString(w.Header().Get(logging.RequestIDHeader)),
+				Method:       nullString(r.Method),
+				Path:         nullString(r.URL.Path),
+				Payload:      payload,
+				Ip:           nullString(ip),
+				ForwardedFor: nullString(r.Header.Get("X-Forwarded-For")),
+				Result:       result,
+				StatusCode:   sql.NullInt32{Int32: int32(status), Valid: true},
+				Error:        nullString(errorText),
+			})
+			if err != nil {
+				logger.ErrorContext(ctx, "write audit event failed", "action", action, "error", err)
+			}
+		})
+	}
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
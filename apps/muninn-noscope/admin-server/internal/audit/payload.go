@@ -0,0 +1,107 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sqlc-dev/pqtype"
+)
+
+const (
+	// maxBodyBytes is how much of a request body is read for the summary; the rest still reaches the handler
+	maxBodyBytes = 1 << 20
+	// maxPayloadBytes bounds the stored summary; larger bodies are reduced to their top-level keys
+	maxPayloadBytes = 4096
+	maxStringLength = 256
+)
+
+// Fields whose names contain any of these are replaced with [redacted]
+var sensitiveFields = []string{"password", "token", "secret", "key"}
+
+// summarizeBody reads the request body, leaves it in place for the handler, and
+// returns a redacted, size-limited JSON summary of it
+func summarizeBody(r *http.Request) pqtype.NullRawMessage {
+	if r.Body == nil || r.Body == http.NoBody {
+		return pqtype.NullRawMessage{}
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodyBytes))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if err != nil || len(body) == 0 {
+		return pqtype.NullRawMessage{}
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return marshalSummary(map[string]interface{}{
+			"bytes":        len(body),
+			"content_type": r.Header.Get("Content-Type"),
+		})
+	}
+	summary := marshalSummary(redact(doc))
+	if len(summary.RawMessage) <= maxPayloadBytes {
+		return summary
+	}
+
+	reduced := map[string]interface{}{"truncated": true, "bytes": len(body)}
+	if obj, ok := doc.(map[string]interface{}); ok {
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		reduced["keys"] = keys
+	}
+	return marshalSummary(reduced)
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if isSensitive(k) {
+				v[k] = "[redacted]"
+			} else {
+				v[k] = redact(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redact(value)
+		}
+	case string:
+		if len(v) > maxStringLength {
+			cut := v[:maxStringLength]
+			for !utf8.ValidString(cut) {
+				cut = cut[:len(cut)-1]
+			}
+			return cut + "..."
+		}
+	}
+	return v
+}
+
+func isSensitive(field string) bool {
+	field = strings.ToLower(field)
+	for _, s := range sensitiveFields {
+		if strings.Contains(field, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func marshalSummary(v interface{}) pqtype.NullRawMessage {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return pqtype.NullRawMessage{}
+	}
+	return pqtype.NullRawMessage{RawMessage: raw, Valid: true}
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"admin-server/internal/database"
+)
+
+// NormalizeEmail is applied before emails are stored or looked up
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// EnsureAdmin creates the first user from ADMIN_EMAIL and ADMIN_PASSWORD when
+// the users table is empty. It does nothing once any user exists.
+func EnsureAdmin(ctx context.Context, q *database.Queries, email, password string) (bool, error) {
+	count, err := q.CountUsers(ctx)
+	if err != nil {
+		return false, fmt.Errorf("count users: %w", err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+	if email == "" || password == "" {
+		return false, fmt.Errorf("no users exist: set ADMIN_EMAIL and ADMIN_PASSWORD to create the first one")
+	}
+	hash, err := HashPassword(password)
+	if err != nil {
+		return false, err
+	}
+	if _, err := q.CreateUser(ctx, database.CreateUserParams{
+		Email:        NormalizeEmail(email),
+		PasswordHash: hash,
+		Role:         string(RoleAdmin),
+	}); err != nil {
+		return false, fmt.Errorf("create admin user: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Principal is the caller of an authenticated request: either a user with a
+// session and role, or an API key with scopes.
+type Principal struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	Role      Role
+	APIKeyID  uuid.UUID
+	Scopes    []Scope
+}
+
+func (p Principal) IsAPIKey() bool {
+	return p.APIKeyID != uuid.Nil
+}
+
+// Can reports whether the principal may use a route open to role and, for API
+// keys, to scope. An empty scope keeps a route to users only.
+func (p Principal) Can(role Role, scope Scope) bool {
+	if p.IsAPIKey() {
+		return scope != "" && slices.Contains(p.Scopes, scope)
+	}
+	return p.Role.Allows(role)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored by the API's authentication middleware
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MinPasswordLength is enforced when passwords are set, not when they are checked
+const MinPasswordLength = 12
+
+const (
+	// apiKeyPrefix marks admin-server keys so they are easy to spot in config and secret scanners
+	apiKeyPrefix       = "nsk_"
+	apiKeyPrefixLength = 12
+)
+
+// dummyHash is compared against when a login names an unknown user, so the
+// response takes as long as a wrong password and does not reveal which emails exist
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("admin-server-dummy-password"), bcrypt.DefaultCost)
+
+// HashPassword returns a bcrypt hash of password
+func HashPassword(password string) (string, error) {
+	if len(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches hash. An empty hash is
+// checked against dummyHash and never matches.
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NewRefreshToken returns a random opaque token for the client and the digest to store
+func NewRefreshToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generate refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken is the digest stored in sessions.refresh_token_hash
+func HashRefreshToken(token string) string {
+	return hashToken(token)
+}
+
+// NewAPIKey returns a random key for the client, the prefix shown in listings and the digest to store
+func NewAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("generate api key: %w", err)
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:apiKeyPrefixLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey is the digest stored in api_keys.key_hash. Keys are random, so a
+// fast hash is enough; bcrypt would slow down every machine request.
+func HashAPIKey(key string) string {
+	return hashToken(key)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
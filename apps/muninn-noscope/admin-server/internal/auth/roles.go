@@ -0,0 +1,38 @@
+package auth
+
+import "fmt"
+
+// Role is a user's access level. Each role includes everything the roles below it can do.
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+// Roles in ascending order of access
+var Roles = []Role{RoleViewer, RoleOperator, RoleAdmin}
+
+func (r Role) rank() int {
+	for i, role := range Roles {
+		if role == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// Allows reports whether r grants at least the access of need
+func (r Role) Allows(need Role) bool {
+	return r.rank() >= 0 && r.rank() >= need.rank()
+}
+
+// ParseRole validates a role name from a request or the database
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if role.rank() < 0 {
+		return "", fmt.Errorf("invalid role %q: expected viewer, operator or admin", s)
+	}
+	return role, nil
+}
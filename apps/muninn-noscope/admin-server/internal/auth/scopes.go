@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Scope is a permission granted to an API key. Keys carry scopes instead of a role.
+type Scope string
+
+const (
+	ScopeTasksRead     Scope = "tasks:read"
+	ScopeTasksWrite    Scope = "tasks:write"
+	ScopeObjectsRead   Scope = "objects:read"
+	ScopeObjectsWrite  Scope = "objects:write"
+	ScopeWorkerControl Scope = "worker:control"
+	ScopeMetricsRead   Scope = "metrics:read"
+)
+
+var Scopes = []Scope{ScopeTasksRead, ScopeTasksWrite, ScopeObjectsRead, ScopeObjectsWrite, ScopeWorkerControl, ScopeMetricsRead}
+
+// ParseScopes validates scope names from a request and removes duplicates
+func ParseScopes(names []string) ([]Scope, error) {
+	var scopes []Scope
+	for _, name := range names {
+		scope := Scope(name)
+		if !slices.Contains(Scopes, scope) {
+			return nil, fmt.Errorf("invalid scope %q", name)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
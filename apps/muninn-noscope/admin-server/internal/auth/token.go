@@ -0,0 +1,79 @@
+// Package auth issues and verifies the admin-server's own access tokens and
+// handles password and refresh token hashing. Upstream credentials such as
+// MUNINN_JWT stay on the server and are never part of anything issued here.
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const issuer = "admin-server"
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are carried by access tokens. The subject is the user ID and SessionID
+// ties the token to a sessions row so logout takes effect before expiry.
+type Claims struct {
+	SessionID string `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// Issuer signs short-lived HS256 access tokens with JWT_SECRET
+type Issuer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewIssuer(secret string, ttl time.Duration) *Issuer {
+	return &Issuer{
+		secret: []byte(secret),
+		ttl:    ttl,
+	}
+}
+
+// Issue returns a signed access token for the user's session and when it expires
+func (i *Issuer) Issue(userID, sessionID uuid.UUID) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(i.ttl)
+	claims := Claims{
+		SessionID: sessionID.String(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sign access token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Parse verifies an access token and returns its user and session IDs
+func (i *Issuer) Parse(token string) (userID, sessionID uuid.UUID, err error) {
+	var claims Claims
+	_, err = jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return i.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidToken
+	}
+	if userID, err = uuid.Parse(claims.Subject); err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidToken
+	}
+	if sessionID, err = uuid.Parse(claims.SessionID); err != nil {
+		return uuid.Nil, uuid.Nil, ErrInvalidToken
+	}
+	return userID, sessionID, nil
+}
@@ -14,47 +14,118 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
+const cancelTask = `-- name: CancelTask :one
+UPDATE tasks
+SET status = 'failed',
+  error = 'cancelled',
+  completed_at = NOW()
+WHERE id = $1
+  AND status = 'pending'
+RETURNING id, object_id, status, input, output, error, created_at, started_at, completed_at, mapping_version, source
+`
+
+func (q *Queries) CancelTask(ctx context.Context, id *uuid.UUID) (Task, error) {
+	row := q.queryRow(ctx, q.cancelTaskStmt, cancelTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ObjectID,
+		&i.Status,
+		&i.Input,
+		&i.Output,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.MappingVersion,
+		&i.Source,
+	)
+	return i, err
+}
+
+const createRollbackTask = `-- name: CreateRollbackTask :one
+INSERT INTO tasks (
+  object_id,
+  status,
+  input,
+  source,
+  started_at
+)
+VALUES (
+  $1,
+  'processing',
+  $2,
+  'rollback',
+  $3
+)
+RETURNING id, object_id, input
+`
+
+type CreateRollbackTaskParams struct {
+	ObjectID  *uuid.UUID      `json:"object_id"`
+	Input     json.RawMessage `json:"input"`
+	StartedAt sql.NullTime    `json:"started_at"`
+}
+
+type CreateRollbackTaskRow struct {
+	ID       *uuid.UUID      `json:"id"`
+	ObjectID *uuid.UUID      `json:"object_id"`
+	Input    json.RawMessage `json:"input"`
+}
+
+func (q *Queries) CreateRollbackTask(ctx context.Context, arg CreateRollbackTaskParams) (CreateRollbackTaskRow, error) {
+	row := q.queryRow(ctx, q.createRollbackTaskStmt, createRollbackTask, arg.ObjectID, arg.Input, arg.StartedAt)
+	var i CreateRollbackTaskRow
+	err := row.Scan(&i.ID, &i.ObjectID, &i.Input)
+	return i, err
+}
+
 const createTask = `-- name: CreateTask :one
 WITH new_task AS (
   INSERT INTO tasks (
     object_id,
     status,
-    input
+    input,
+    source
   )
   SELECT 
     $1,
     'pending',
-    $2
+    $2,
+    $3
   WHERE NOT EXISTS (
     SELECT 1 
     FROM tasks 
     WHERE object_id = $1 
     AND status = 'pending'
   )
-  RETURNING id, object_id, status, input, output, error, created_at, started_at, completed_at
+  RETURNING id, object_id, status, input, output, error, created_at, started_at, completed_at, mapping_version, source
 )
-SELECT id, object_id, status, input, output, error, created_at, started_at, completed_at FROM new_task
+SELECT id, object_id, status, input, output, error, created_at, started_at, completed_at, mapping_version, source FROM new_task
 `
 
 type CreateTaskParams struct {
 	ObjectID *uuid.UUID      `json:"object_id"`
 	Input    json.RawMessage `json:"input"`
+	Source   string          `json:"source"`
 }
 
 type CreateTaskRow struct {
-	ID          *uuid.UUID            `json:"id"`
-	ObjectID    *uuid.UUID            `json:"object_id"`
-	Status      string                `json:"status"`
-	Input       json.RawMessage       `json:"input"`
-	Output      pqtype.NullRawMessage `json:"output"`
-	Error       sql.NullString        `json:"error"`
-	CreatedAt   sql.NullTime          `json:"created_at"`
-	StartedAt   sql.NullTime          `json:"started_at"`
-	CompletedAt sql.NullTime          `json:"completed_at"`
+	ID             *uuid.UUID            `json:"id"`
+	ObjectID       *uuid.UUID            `json:"object_id"`
+	Status         string                `json:"status"`
+	Input          json.RawMessage       `json:"input"`
+	Output         pqtype.NullRawMessage `json:"output"`
+	Error          sql.NullString        `json:"error"`
+	CreatedAt      sql.NullTime          `json:"created_at"`
+	StartedAt      sql.NullTime          `json:"started_at"`
+	CompletedAt    sql.NullTime          `json:"completed_at"`
+	MappingVersion sql.NullString        `json:"mapping_version"`
+	Source         string                `json:"source"`
 }
 
 func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (CreateTaskRow, error) {
-	row := q.queryRow(ctx, q.createTaskStmt, createTask, arg.ObjectID, arg.Input)
+	row := q.queryRow(ctx, q.createTaskStmt, createTask, arg.ObjectID, arg.Input, arg.Source)
 	var i CreateTaskRow
 	err := row.Scan(
 		&i.ID,
@@ -66,6 +137,72 @@ func (q *Queries) CreateTask(ctx context.Context, arg CreateTaskParams) (CreateT
 		&i.CreatedAt,
 		&i.StartedAt,
 		&i.CompletedAt,
+		&i.MappingVersion,
+		&i.Source,
+	)
+	return i, err
+}
+
+const failInterruptedRollbacks = `-- name: FailInterruptedRollbacks :execrows
+UPDATE tasks
+SET status = 'failed',
+  error = 'rollback interrupted',
+  completed_at = NOW()
+WHERE object_id = $1
+  AND source = 'rollback'
+  AND status = 'processing'
+  AND started_at < $2
+`
+
+type FailInterruptedRollbacksParams struct {
+	ObjectID  *uuid.UUID   `json:"object_id"`
+	StartedAt sql.NullTime `json:"started_at"`
+}
+
+func (q *Queries) FailInterruptedRollbacks(ctx context.Context, arg FailInterruptedRollbacksParams) (int64, error) {
+	result, err := q.exec(ctx, q.failInterruptedRollbacksStmt, failInterruptedRollbacks, arg.ObjectID, arg.StartedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const requeueFinishedTask = `-- name: RequeueFinishedTask :one
+UPDATE tasks
+SET status = 'pending',
+  output = NULL,
+  error = NULL,
+  started_at = NULL,
+  completed_at = NULL,
+  mapping_version = NULL
+WHERE id = $1
+  AND status IN ('failed', 'completed', 'unchanged')
+  AND source <> 'rollback'
+  AND NOT EXISTS (
+    SELECT 1
+    FROM tasks pending
+    WHERE pending.object_id = tasks.object_id
+    AND pending.status = 'pending'
+  )
+RETURNING id, object_id, status, input, output, error, created_at, started_at, completed_at, mapping_version, source
+`
+
+// Rollback tasks are not requeued: the worker would treat their input as an enrichment
+func (q *Queries) RequeueFinishedTask(ctx context.Context, id *uuid.UUID) (Task, error) {
+	row := q.queryRow(ctx, q.requeueFinishedTaskStmt, requeueFinishedTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ObjectID,
+		&i.Status,
+		&i.Input,
+		&i.Output,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.MappingVersion,
+		&i.Source,
 	)
 	return i, err
 }
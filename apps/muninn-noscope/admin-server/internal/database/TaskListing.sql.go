@@ -7,73 +7,34 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
-const countTasks = `-- name: CountTasks :one
-SELECT COUNT(*)
+const countTasksByStatusForObject = `-- name: CountTasksByStatusForObject :many
+SELECT status, COUNT(*)
 FROM tasks
-WHERE 
-    ($1::uuid IS NULL OR object_id = $1::uuid) AND
-    ($2::text = '' OR status = $2::text)
+WHERE object_id = $1
+GROUP BY status
+ORDER BY status
 `
 
-type CountTasksParams struct {
-	Column1 *uuid.UUID `json:"column_1"`
-	Column2 string     `json:"column_2"`
+type CountTasksByStatusForObjectRow struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
 }
 
-func (q *Queries) CountTasks(ctx context.Context, arg CountTasksParams) (int64, error) {
-	row := q.queryRow(ctx, q.countTasksStmt, countTasks, arg.Column1, arg.Column2)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
-const listTasks = `-- name: ListTasks :many
-SELECT id, object_id, status, input, output, error, created_at, started_at, completed_at
-FROM tasks
-WHERE 
-    ($1::uuid IS NULL OR object_id = $1::uuid) AND
-    ($2::text = '' OR status = $2::text)
-ORDER BY created_at DESC
-LIMIT $3
-OFFSET $4
-`
-
-type ListTasksParams struct {
-	Column1 *uuid.UUID `json:"column_1"`
-	Column2 string     `json:"column_2"`
-	Limit   int32      `json:"limit"`
-	Offset  int32      `json:"offset"`
-}
-
-func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error) {
-	rows, err := q.query(ctx, q.listTasksStmt, listTasks,
-		arg.Column1,
-		arg.Column2,
-		arg.Limit,
-		arg.Offset,
-	)
+func (q *Queries) CountTasksByStatusForObject(ctx context.Context, objectID *uuid.UUID) ([]CountTasksByStatusForObjectRow, error) {
+	rows, err := q.query(ctx, q.countTasksByStatusForObjectStmt, countTasksByStatusForObject, objectID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Task
+	var items []CountTasksByStatusForObjectRow
 	for rows.Next() {
-		var i Task
-		if err := rows.Scan(
-			&i.ID,
-			&i.ObjectID,
-			&i.Status,
-			&i.Input,
-			&i.Output,
-			&i.Error,
-			&i.CreatedAt,
-			&i.StartedAt,
-			&i.CompletedAt,
-		); err != nil {
+		var i CountTasksByStatusForObjectRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -86,3 +47,76 @@ func (q *Queries) ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, e
 	}
 	return items, nil
 }
+
+const getLatestTaskErrorForObject = `-- name: GetLatestTaskErrorForObject :one
+SELECT error, completed_at
+FROM tasks
+WHERE object_id = $1
+AND error IS NOT NULL
+ORDER BY completed_at DESC NULLS LAST
+LIMIT 1
+`
+
+type GetLatestTaskErrorForObjectRow struct {
+	Error       sql.NullString `json:"error"`
+	CompletedAt sql.NullTime   `json:"completed_at"`
+}
+
+func (q *Queries) GetLatestTaskErrorForObject(ctx context.Context, objectID *uuid.UUID) (GetLatestTaskErrorForObjectRow, error) {
+	row := q.queryRow(ctx, q.getLatestTaskErrorForObjectStmt, getLatestTaskErrorForObject, objectID)
+	var i GetLatestTaskErrorForObjectRow
+	err := row.Scan(&i.Error, &i.CompletedAt)
+	return i, err
+}
+
+const getLatestTaskForObject = `-- name: GetLatestTaskForObject :one
+SELECT id, object_id, status, input, output, error, created_at, started_at, completed_at, mapping_version, source
+FROM tasks
+WHERE object_id = $1
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestTaskForObject(ctx context.Context, objectID *uuid.UUID) (Task, error) {
+	row := q.queryRow(ctx, q.getLatestTaskForObjectStmt, getLatestTaskForObject, objectID)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ObjectID,
+		&i.Status,
+		&i.Input,
+		&i.Output,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.MappingVersion,
+		&i.Source,
+	)
+	return i, err
+}
+
+const getTask = `-- name: GetTask :one
+SELECT id, object_id, status, input, output, error, created_at, started_at, completed_at, mapping_version, source
+FROM tasks
+WHERE id = $1
+`
+
+func (q *Queries) GetTask(ctx context.Context, id *uuid.UUID) (Task, error) {
+	row := q.queryRow(ctx, q.getTaskStmt, getTask, id)
+	var i Task
+	err := row.Scan(
+		&i.ID,
+		&i.ObjectID,
+		&i.Status,
+		&i.Input,
+		&i.Output,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+		&i.MappingVersion,
+		&i.Source,
+	)
+	return i, err
+}
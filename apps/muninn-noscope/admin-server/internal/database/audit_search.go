@@ -0,0 +1,141 @@
+package database
+
+// Hand-written: audit event filters are assembled dynamically, see pagination.go.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AuditResults lists every value allowed by audit_events_result_check
+var AuditResults = []string{"success", "failure", "denied"}
+
+// AuditActorTypes lists every value allowed by audit_events_actor_type_check
+var AuditActorTypes = []string{"anonymous", "user", "api_key"}
+
+var auditSort = sortColumn{Expr: "a.occurred_at", Type: "timestamptz"}
+
+// AuditFilter narrows SearchAuditEvents; the zero value lists every event, newest first
+type AuditFilter struct {
+	ActorType  string
+	ActorID    *uuid.UUID
+	Actions    []string
+	TargetType string
+	TargetID   string
+	Result     string
+	Occurred   TimeRange
+	SortAsc    bool
+}
+
+const auditSearchFrom = `
+FROM audit_events a`
+
+func (f AuditFilter) where() ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.ActorType != "" {
+		conds = append(conds, "a.actor_type = "+arg(f.ActorType))
+	}
+	if f.ActorID != nil {
+		conds = append(conds, "a.actor_id = "+arg(f.ActorID))
+	}
+	if len(f.Actions) == 1 {
+		conds = append(conds, "a.action = "+arg(f.Actions[0]))
+	} else if len(f.Actions) > 1 {
+		conds = append(conds, "a.action = ANY("+arg(pq.Array(f.Actions))+"::text[])")
+	}
+	if f.TargetType != "" {
+		conds = append(conds, "a.target_type = "+arg(f.TargetType))
+	}
+	if f.TargetID != "" {
+		conds = append(conds, "a.target_id = "+arg(f.TargetID))
+	}
+	if f.Result != "" {
+		conds = append(conds, "a.result = "+arg(f.Result))
+	}
+	if f.Occurred.After != nil {
+		conds = append(conds, "a.occurred_at >= "+arg(*f.Occurred.After))
+	}
+	if f.Occurred.Before != nil {
+		conds = append(conds, "a.occurred_at < "+arg(*f.Occurred.Before))
+	}
+	return conds, args
+}
+
+func (f AuditFilter) pageQuery(p Page) pageQuery {
+	conds, args := f.where()
+	return pageQuery{
+		Columns: "a.id, a.occurred_at, a.actor_type, a.actor_id, a.actor_name, a.action, a.target_type, a.target_id, a.request_id, a.method, a.path, a.payload, a.ip, a.forwarded_for, a.result, a.status_code, a.error",
+		From:    auditSearchFrom,
+		Conds:   conds,
+		Args:    args,
+		Sort:    auditSort,
+		SortBy:  "occurred_at",
+		Asc:     f.SortAsc,
+		IDExpr:  "a.id",
+		Page:    p,
+	}
+}
+
+func (q *Queries) SearchAuditEvents(ctx context.Context, f AuditFilter, p Page) ([]AuditEvent, PageCursors, error) {
+	page := f.pageQuery(p)
+	query, args := page.build()
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, PageCursors{}, err
+	}
+	defer rows.Close()
+	var items []AuditEvent
+	var keys []pageKey
+	for rows.Next() {
+		var i AuditEvent
+		var key string
+		if err := rows.Scan(
+			&i.ID,
+			&i.OccurredAt,
+			&i.ActorType,
+			&i.ActorID,
+			&i.ActorName,
+			&i.Action,
+			&i.TargetType,
+			&i.TargetID,
+			&i.RequestID,
+			&i.Method,
+			&i.Path,
+			&i.Payload,
+			&i.Ip,
+			&i.ForwardedFor,
+			&i.Result,
+			&i.StatusCode,
+			&i.Error,
+			&key,
+		); err != nil {
+			return nil, PageCursors{}, err
+		}
+		items = append(items, i)
+		keys = append(keys, pageKey{Key: key, ID: *i.ID})
+	}
+	if err := rows.Close(); err != nil {
+		return nil, PageCursors{}, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, PageCursors{}, err
+	}
+
+	items, cursors := finishPage(page, items, keys)
+	return items, cursors, nil
+}
+
+func (q *Queries) CountSearchAuditEvents(ctx context.Context, f AuditFilter, mode CountMode) (*int64, error) {
+	conds, args := f.where()
+	return q.count(ctx, mode, auditSearchFrom, conds, args)
+}
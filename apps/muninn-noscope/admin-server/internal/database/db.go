@@ -24,42 +24,189 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
-	if q.countObjectsStmt, err = db.PrepareContext(ctx, countObjects); err != nil {
-		return nil, fmt.Errorf("error preparing query CountObjects: %w", err)
+	if q.cancelTaskStmt, err = db.PrepareContext(ctx, cancelTask); err != nil {
+		return nil, fmt.Errorf("error preparing query CancelTask: %w", err)
 	}
-	if q.countTasksStmt, err = db.PrepareContext(ctx, countTasks); err != nil {
-		return nil, fmt.Errorf("error preparing query CountTasks: %w", err)
+	if q.countStaleObjectsStmt, err = db.PrepareContext(ctx, countStaleObjects); err != nil {
+		return nil, fmt.Errorf("error preparing query CountStaleObjects: %w", err)
+	}
+	if q.countTasksByStatusStmt, err = db.PrepareContext(ctx, countTasksByStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query CountTasksByStatus: %w", err)
+	}
+	if q.countTasksByStatusAndSourceStmt, err = db.PrepareContext(ctx, countTasksByStatusAndSource); err != nil {
+		return nil, fmt.Errorf("error preparing query CountTasksByStatusAndSource: %w", err)
+	}
+	if q.countTasksByStatusForObjectStmt, err = db.PrepareContext(ctx, countTasksByStatusForObject); err != nil {
+		return nil, fmt.Errorf("error preparing query CountTasksByStatusForObject: %w", err)
+	}
+	if q.countUsersStmt, err = db.PrepareContext(ctx, countUsers); err != nil {
+		return nil, fmt.Errorf("error preparing query CountUsers: %w", err)
+	}
+	if q.createAPIKeyStmt, err = db.PrepareContext(ctx, createAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAPIKey: %w", err)
+	}
+	if q.createAuditEventStmt, err = db.PrepareContext(ctx, createAuditEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAuditEvent: %w", err)
+	}
+	if q.createBlockedTagStmt, err = db.PrepareContext(ctx, createBlockedTag); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBlockedTag: %w", err)
 	}
 	if q.createObjectStmt, err = db.PrepareContext(ctx, createObject); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateObject: %w", err)
 	}
+	if q.createObjectEnrichmentStmt, err = db.PrepareContext(ctx, createObjectEnrichment); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateObjectEnrichment: %w", err)
+	}
+	if q.createObjectTagStmt, err = db.PrepareContext(ctx, createObjectTag); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateObjectTag: %w", err)
+	}
+	if q.createRollbackTaskStmt, err = db.PrepareContext(ctx, createRollbackTask); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateRollbackTask: %w", err)
+	}
 	if q.createScanLogStmt, err = db.PrepareContext(ctx, createScanLog); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateScanLog: %w", err)
 	}
+	if q.createSessionStmt, err = db.PrepareContext(ctx, createSession); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSession: %w", err)
+	}
+	if q.createSettingChangeStmt, err = db.PrepareContext(ctx, createSettingChange); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSettingChange: %w", err)
+	}
 	if q.createTaskStmt, err = db.PrepareContext(ctx, createTask); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTask: %w", err)
 	}
+	if q.createUserStmt, err = db.PrepareContext(ctx, createUser); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateUser: %w", err)
+	}
+	if q.deleteBlockedTagStmt, err = db.PrepareContext(ctx, deleteBlockedTag); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBlockedTag: %w", err)
+	}
+	if q.deleteObjectTagStmt, err = db.PrepareContext(ctx, deleteObjectTag); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteObjectTag: %w", err)
+	}
+	if q.deleteSecretStmt, err = db.PrepareContext(ctx, deleteSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSecret: %w", err)
+	}
+	if q.deleteSettingStmt, err = db.PrepareContext(ctx, deleteSetting); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteSetting: %w", err)
+	}
+	if q.deleteTagAliasStmt, err = db.PrepareContext(ctx, deleteTagAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTagAlias: %w", err)
+	}
+	if q.failInterruptedRollbacksStmt, err = db.PrepareContext(ctx, failInterruptedRollbacks); err != nil {
+		return nil, fmt.Errorf("error preparing query FailInterruptedRollbacks: %w", err)
+	}
+	if q.getActiveAPIKeyStmt, err = db.PrepareContext(ctx, getActiveAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveAPIKey: %w", err)
+	}
+	if q.getActiveSessionUserStmt, err = db.PrepareContext(ctx, getActiveSessionUser); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveSessionUser: %w", err)
+	}
+	if q.getLatestScanLogStmt, err = db.PrepareContext(ctx, getLatestScanLog); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestScanLog: %w", err)
+	}
 	if q.getLatestScanTimeStmt, err = db.PrepareContext(ctx, getLatestScanTime); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLatestScanTime: %w", err)
 	}
+	if q.getLatestTaskErrorForObjectStmt, err = db.PrepareContext(ctx, getLatestTaskErrorForObject); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestTaskErrorForObject: %w", err)
+	}
+	if q.getLatestTaskForObjectStmt, err = db.PrepareContext(ctx, getLatestTaskForObject); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestTaskForObject: %w", err)
+	}
 	if q.getObjectStmt, err = db.PrepareContext(ctx, getObject); err != nil {
 		return nil, fmt.Errorf("error preparing query GetObject: %w", err)
 	}
+	if q.getObjectEnrichmentByTaskStmt, err = db.PrepareContext(ctx, getObjectEnrichmentByTask); err != nil {
+		return nil, fmt.Errorf("error preparing query GetObjectEnrichmentByTask: %w", err)
+	}
+	if q.getObjectEnrichmentByVersionStmt, err = db.PrepareContext(ctx, getObjectEnrichmentByVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetObjectEnrichmentByVersion: %w", err)
+	}
+	if q.getSettingForUpdateStmt, err = db.PrepareContext(ctx, getSettingForUpdate); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSettingForUpdate: %w", err)
+	}
 	if q.getStaleObjectsStmt, err = db.PrepareContext(ctx, getStaleObjects); err != nil {
 		return nil, fmt.Errorf("error preparing query GetStaleObjects: %w", err)
 	}
+	if q.getTaskStmt, err = db.PrepareContext(ctx, getTask); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTask: %w", err)
+	}
+	if q.getTaskBacklogStmt, err = db.PrepareContext(ctx, getTaskBacklog); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTaskBacklog: %w", err)
+	}
+	if q.getTaskOutcomeStatsStmt, err = db.PrepareContext(ctx, getTaskOutcomeStats); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTaskOutcomeStats: %w", err)
+	}
+	if q.getTaskThroughputStmt, err = db.PrepareContext(ctx, getTaskThroughput); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTaskThroughput: %w", err)
+	}
+	if q.getUserStmt, err = db.PrepareContext(ctx, getUser); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUser: %w", err)
+	}
+	if q.getUserByEmailStmt, err = db.PrepareContext(ctx, getUserByEmail); err != nil {
+		return nil, fmt.Errorf("error preparing query GetUserByEmail: %w", err)
+	}
 	if q.healthCheckStmt, err = db.PrepareContext(ctx, healthCheck); err != nil {
 		return nil, fmt.Errorf("error preparing query HealthCheck: %w", err)
 	}
-	if q.listObjectsStmt, err = db.PrepareContext(ctx, listObjects); err != nil {
-		return nil, fmt.Errorf("error preparing query ListObjects: %w", err)
+	if q.listAPIKeysStmt, err = db.PrepareContext(ctx, listAPIKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAPIKeys: %w", err)
+	}
+	if q.listBlockedTagsStmt, err = db.PrepareContext(ctx, listBlockedTags); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBlockedTags: %w", err)
+	}
+	if q.listObjectEnrichmentsStmt, err = db.PrepareContext(ctx, listObjectEnrichments); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectEnrichments: %w", err)
+	}
+	if q.listObjectTagsStmt, err = db.PrepareContext(ctx, listObjectTags); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectTags: %w", err)
+	}
+	if q.listSecretsStmt, err = db.PrepareContext(ctx, listSecrets); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSecrets: %w", err)
+	}
+	if q.listSettingChangesStmt, err = db.PrepareContext(ctx, listSettingChanges); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSettingChanges: %w", err)
+	}
+	if q.listSettingsStmt, err = db.PrepareContext(ctx, listSettings); err != nil {
+		return nil, fmt.Errorf("error preparing query ListSettings: %w", err)
+	}
+	if q.listTagAliasesStmt, err = db.PrepareContext(ctx, listTagAliases); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTagAliases: %w", err)
+	}
+	if q.listUsersStmt, err = db.PrepareContext(ctx, listUsers); err != nil {
+		return nil, fmt.Errorf("error preparing query ListUsers: %w", err)
 	}
-	if q.listTasksStmt, err = db.PrepareContext(ctx, listTasks); err != nil {
-		return nil, fmt.Errorf("error preparing query ListTasks: %w", err)
+	if q.lockObjectForEnrichmentStmt, err = db.PrepareContext(ctx, lockObjectForEnrichment); err != nil {
+		return nil, fmt.Errorf("error preparing query LockObjectForEnrichment: %w", err)
 	}
 	if q.objectsSyncLast60daysStmt, err = db.PrepareContext(ctx, objectsSyncLast60days); err != nil {
 		return nil, fmt.Errorf("error preparing query ObjectsSyncLast60days: %w", err)
 	}
+	if q.requeueFinishedTaskStmt, err = db.PrepareContext(ctx, requeueFinishedTask); err != nil {
+		return nil, fmt.Errorf("error preparing query RequeueFinishedTask: %w", err)
+	}
+	if q.requeueTaskStmt, err = db.PrepareContext(ctx, requeueTask); err != nil {
+		return nil, fmt.Errorf("error preparing query RequeueTask: %w", err)
+	}
+	if q.revokeAPIKeyStmt, err = db.PrepareContext(ctx, revokeAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAPIKey: %w", err)
+	}
+	if q.revokeSessionStmt, err = db.PrepareContext(ctx, revokeSession); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeSession: %w", err)
+	}
+	if q.rotateAPIKeyStmt, err = db.PrepareContext(ctx, rotateAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query RotateAPIKey: %w", err)
+	}
+	if q.rotateSessionRefreshTokenStmt, err = db.PrepareContext(ctx, rotateSessionRefreshToken); err != nil {
+		return nil, fmt.Errorf("error preparing query RotateSessionRefreshToken: %w", err)
+	}
+	if q.touchAPIKeyStmt, err = db.PrepareContext(ctx, touchAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query TouchAPIKey: %w", err)
+	}
+	if q.updateObjectContentHashStmt, err = db.PrepareContext(ctx, updateObjectContentHash); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateObjectContentHash: %w", err)
+	}
 	if q.updateObjectLastSyncedAtStmt, err = db.PrepareContext(ctx, updateObjectLastSyncedAt); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateObjectLastSyncedAt: %w", err)
 	}
@@ -69,19 +216,69 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateTaskStatusStmt, err = db.PrepareContext(ctx, updateTaskStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateTaskStatus: %w", err)
 	}
+	if q.updateUserLastLoginStmt, err = db.PrepareContext(ctx, updateUserLastLogin); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserLastLogin: %w", err)
+	}
+	if q.updateUserRoleStmt, err = db.PrepareContext(ctx, updateUserRole); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateUserRole: %w", err)
+	}
+	if q.upsertSecretStmt, err = db.PrepareContext(ctx, upsertSecret); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertSecret: %w", err)
+	}
+	if q.upsertSettingStmt, err = db.PrepareContext(ctx, upsertSetting); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertSetting: %w", err)
+	}
+	if q.upsertTagAliasStmt, err = db.PrepareContext(ctx, upsertTagAlias); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertTagAlias: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
-	if q.countObjectsStmt != nil {
-		if cerr := q.countObjectsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing countObjectsStmt: %w", cerr)
+	if q.cancelTaskStmt != nil {
+		if cerr := q.cancelTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cancelTaskStmt: %w", cerr)
+		}
+	}
+	if q.countStaleObjectsStmt != nil {
+		if cerr := q.countStaleObjectsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countStaleObjectsStmt: %w", cerr)
+		}
+	}
+	if q.countTasksByStatusStmt != nil {
+		if cerr := q.countTasksByStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countTasksByStatusStmt: %w", cerr)
+		}
+	}
+	if q.countTasksByStatusAndSourceStmt != nil {
+		if cerr := q.countTasksByStatusAndSourceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countTasksByStatusAndSourceStmt: %w", cerr)
+		}
+	}
+	if q.countTasksByStatusForObjectStmt != nil {
+		if cerr := q.countTasksByStatusForObjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countTasksByStatusForObjectStmt: %w", cerr)
+		}
+	}
+	if q.countUsersStmt != nil {
+		if cerr := q.countUsersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing countUsersStmt: %w", cerr)
+		}
+	}
+	if q.createAPIKeyStmt != nil {
+		if cerr := q.createAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAPIKeyStmt: %w", cerr)
 		}
 	}
-	if q.countTasksStmt != nil {
-		if cerr := q.countTasksStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing countTasksStmt: %w", cerr)
+	if q.createAuditEventStmt != nil {
+		if cerr := q.createAuditEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAuditEventStmt: %w", cerr)
+		}
+	}
+	if q.createBlockedTagStmt != nil {
+		if cerr := q.createBlockedTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBlockedTagStmt: %w", cerr)
 		}
 	}
 	if q.createObjectStmt != nil {
@@ -89,44 +286,214 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createObjectStmt: %w", cerr)
 		}
 	}
+	if q.createObjectEnrichmentStmt != nil {
+		if cerr := q.createObjectEnrichmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createObjectEnrichmentStmt: %w", cerr)
+		}
+	}
+	if q.createObjectTagStmt != nil {
+		if cerr := q.createObjectTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createObjectTagStmt: %w", cerr)
+		}
+	}
+	if q.createRollbackTaskStmt != nil {
+		if cerr := q.createRollbackTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createRollbackTaskStmt: %w", cerr)
+		}
+	}
 	if q.createScanLogStmt != nil {
 		if cerr := q.createScanLogStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createScanLogStmt: %w", cerr)
 		}
 	}
+	if q.createSessionStmt != nil {
+		if cerr := q.createSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSessionStmt: %w", cerr)
+		}
+	}
+	if q.createSettingChangeStmt != nil {
+		if cerr := q.createSettingChangeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSettingChangeStmt: %w", cerr)
+		}
+	}
 	if q.createTaskStmt != nil {
 		if cerr := q.createTaskStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createTaskStmt: %w", cerr)
 		}
 	}
+	if q.createUserStmt != nil {
+		if cerr := q.createUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createUserStmt: %w", cerr)
+		}
+	}
+	if q.deleteBlockedTagStmt != nil {
+		if cerr := q.deleteBlockedTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBlockedTagStmt: %w", cerr)
+		}
+	}
+	if q.deleteObjectTagStmt != nil {
+		if cerr := q.deleteObjectTagStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteObjectTagStmt: %w", cerr)
+		}
+	}
+	if q.deleteSecretStmt != nil {
+		if cerr := q.deleteSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSecretStmt: %w", cerr)
+		}
+	}
+	if q.deleteSettingStmt != nil {
+		if cerr := q.deleteSettingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteSettingStmt: %w", cerr)
+		}
+	}
+	if q.deleteTagAliasStmt != nil {
+		if cerr := q.deleteTagAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTagAliasStmt: %w", cerr)
+		}
+	}
+	if q.failInterruptedRollbacksStmt != nil {
+		if cerr := q.failInterruptedRollbacksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failInterruptedRollbacksStmt: %w", cerr)
+		}
+	}
+	if q.getActiveAPIKeyStmt != nil {
+		if cerr := q.getActiveAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.getActiveSessionUserStmt != nil {
+		if cerr := q.getActiveSessionUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveSessionUserStmt: %w", cerr)
+		}
+	}
+	if q.getLatestScanLogStmt != nil {
+		if cerr := q.getLatestScanLogStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestScanLogStmt: %w", cerr)
+		}
+	}
 	if q.getLatestScanTimeStmt != nil {
 		if cerr := q.getLatestScanTimeStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getLatestScanTimeStmt: %w", cerr)
 		}
 	}
+	if q.getLatestTaskErrorForObjectStmt != nil {
+		if cerr := q.getLatestTaskErrorForObjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestTaskErrorForObjectStmt: %w", cerr)
+		}
+	}
+	if q.getLatestTaskForObjectStmt != nil {
+		if cerr := q.getLatestTaskForObjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestTaskForObjectStmt: %w", cerr)
+		}
+	}
 	if q.getObjectStmt != nil {
 		if cerr := q.getObjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getObjectStmt: %w", cerr)
 		}
 	}
+	if q.getObjectEnrichmentByTaskStmt != nil {
+		if cerr := q.getObjectEnrichmentByTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getObjectEnrichmentByTaskStmt: %w", cerr)
+		}
+	}
+	if q.getObjectEnrichmentByVersionStmt != nil {
+		if cerr := q.getObjectEnrichmentByVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getObjectEnrichmentByVersionStmt: %w", cerr)
+		}
+	}
+	if q.getSettingForUpdateStmt != nil {
+		if cerr := q.getSettingForUpdateStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSettingForUpdateStmt: %w", cerr)
+		}
+	}
 	if q.getStaleObjectsStmt != nil {
 		if cerr := q.getStaleObjectsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getStaleObjectsStmt: %w", cerr)
 		}
 	}
+	if q.getTaskStmt != nil {
+		if cerr := q.getTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTaskStmt: %w", cerr)
+		}
+	}
+	if q.getTaskBacklogStmt != nil {
+		if cerr := q.getTaskBacklogStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTaskBacklogStmt: %w", cerr)
+		}
+	}
+	if q.getTaskOutcomeStatsStmt != nil {
+		if cerr := q.getTaskOutcomeStatsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTaskOutcomeStatsStmt: %w", cerr)
+		}
+	}
+	if q.getTaskThroughputStmt != nil {
+		if cerr := q.getTaskThroughputStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTaskThroughputStmt: %w", cerr)
+		}
+	}
+	if q.getUserStmt != nil {
+		if cerr := q.getUserStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserStmt: %w", cerr)
+		}
+	}
+	if q.getUserByEmailStmt != nil {
+		if cerr := q.getUserByEmailStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getUserByEmailStmt: %w", cerr)
+		}
+	}
 	if q.healthCheckStmt != nil {
 		if cerr := q.healthCheckStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing healthCheckStmt: %w", cerr)
 		}
 	}
-	if q.listObjectsStmt != nil {
-		if cerr := q.listObjectsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing listObjectsStmt: %w", cerr)
+	if q.listAPIKeysStmt != nil {
+		if cerr := q.listAPIKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAPIKeysStmt: %w", cerr)
+		}
+	}
+	if q.listBlockedTagsStmt != nil {
+		if cerr := q.listBlockedTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBlockedTagsStmt: %w", cerr)
 		}
 	}
-	if q.listTasksStmt != nil {
-		if cerr := q.listTasksStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing listTasksStmt: %w", cerr)
+	if q.listObjectEnrichmentsStmt != nil {
+		if cerr := q.listObjectEnrichmentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectEnrichmentsStmt: %w", cerr)
+		}
+	}
+	if q.listObjectTagsStmt != nil {
+		if cerr := q.listObjectTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectTagsStmt: %w", cerr)
+		}
+	}
+	if q.listSecretsStmt != nil {
+		if cerr := q.listSecretsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSecretsStmt: %w", cerr)
+		}
+	}
+	if q.listSettingChangesStmt != nil {
+		if cerr := q.listSettingChangesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSettingChangesStmt: %w", cerr)
+		}
+	}
+	if q.listSettingsStmt != nil {
+		if cerr := q.listSettingsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listSettingsStmt: %w", cerr)
+		}
+	}
+	if q.listTagAliasesStmt != nil {
+		if cerr := q.listTagAliasesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTagAliasesStmt: %w", cerr)
+		}
+	}
+	if q.listUsersStmt != nil {
+		if cerr := q.listUsersStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listUsersStmt: %w", cerr)
+		}
+	}
+	if q.lockObjectForEnrichmentStmt != nil {
+		if cerr := q.lockObjectForEnrichmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing lockObjectForEnrichmentStmt: %w", cerr)
 		}
 	}
 	if q.objectsSyncLast60daysStmt != nil {
@@ -134,6 +501,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing objectsSyncLast60daysStmt: %w", cerr)
 		}
 	}
+	if q.requeueFinishedTaskStmt != nil {
+		if cerr := q.requeueFinishedTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing requeueFinishedTaskStmt: %w", cerr)
+		}
+	}
+	if q.requeueTaskStmt != nil {
+		if cerr := q.requeueTaskStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing requeueTaskStmt: %w", cerr)
+		}
+	}
+	if q.revokeAPIKeyStmt != nil {
+		if cerr := q.revokeAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.revokeSessionStmt != nil {
+		if cerr := q.revokeSessionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeSessionStmt: %w", cerr)
+		}
+	}
+	if q.rotateAPIKeyStmt != nil {
+		if cerr := q.rotateAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rotateAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.rotateSessionRefreshTokenStmt != nil {
+		if cerr := q.rotateSessionRefreshTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rotateSessionRefreshTokenStmt: %w", cerr)
+		}
+	}
+	if q.touchAPIKeyStmt != nil {
+		if cerr := q.touchAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing touchAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.updateObjectContentHashStmt != nil {
+		if cerr := q.updateObjectContentHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateObjectContentHashStmt: %w", cerr)
+		}
+	}
 	if q.updateObjectLastSyncedAtStmt != nil {
 		if cerr := q.updateObjectLastSyncedAtStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateObjectLastSyncedAtStmt: %w", cerr)
@@ -149,6 +556,31 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateTaskStatusStmt: %w", cerr)
 		}
 	}
+	if q.updateUserLastLoginStmt != nil {
+		if cerr := q.updateUserLastLoginStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserLastLoginStmt: %w", cerr)
+		}
+	}
+	if q.updateUserRoleStmt != nil {
+		if cerr := q.updateUserRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateUserRoleStmt: %w", cerr)
+		}
+	}
+	if q.upsertSecretStmt != nil {
+		if cerr := q.upsertSecretStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertSecretStmt: %w", cerr)
+		}
+	}
+	if q.upsertSettingStmt != nil {
+		if cerr := q.upsertSettingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertSettingStmt: %w", cerr)
+		}
+	}
+	if q.upsertTagAliasStmt != nil {
+		if cerr := q.upsertTagAliasStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertTagAliasStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -186,43 +618,151 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                           DBTX
-	tx                           *sql.Tx
-	countObjectsStmt             *sql.Stmt
-	countTasksStmt               *sql.Stmt
-	createObjectStmt             *sql.Stmt
-	createScanLogStmt            *sql.Stmt
-	createTaskStmt               *sql.Stmt
-	getLatestScanTimeStmt        *sql.Stmt
-	getObjectStmt                *sql.Stmt
-	getStaleObjectsStmt          *sql.Stmt
-	healthCheckStmt              *sql.Stmt
-	listObjectsStmt              *sql.Stmt
-	listTasksStmt                *sql.Stmt
-	objectsSyncLast60daysStmt    *sql.Stmt
-	updateObjectLastSyncedAtStmt *sql.Stmt
-	updateTaskProcessingStmt     *sql.Stmt
-	updateTaskStatusStmt         *sql.Stmt
+	db                               DBTX
+	tx                               *sql.Tx
+	cancelTaskStmt                   *sql.Stmt
+	countStaleObjectsStmt            *sql.Stmt
+	countTasksByStatusStmt           *sql.Stmt
+	countTasksByStatusAndSourceStmt  *sql.Stmt
+	countTasksByStatusForObjectStmt  *sql.Stmt
+	countUsersStmt                   *sql.Stmt
+	createAPIKeyStmt                 *sql.Stmt
+	createAuditEventStmt             *sql.Stmt
+	createBlockedTagStmt             *sql.Stmt
+	createObjectStmt                 *sql.Stmt
+	createObjectEnrichmentStmt       *sql.Stmt
+	createObjectTagStmt              *sql.Stmt
+	createRollbackTaskStmt           *sql.Stmt
+	createScanLogStmt                *sql.Stmt
+	createSessionStmt                *sql.Stmt
+	createSettingChangeStmt          *sql.Stmt
+	createTaskStmt                   *sql.Stmt
+	createUserStmt                   *sql.Stmt
+	deleteBlockedTagStmt             *sql.Stmt
+	deleteObjectTagStmt              *sql.Stmt
+	deleteSecretStmt                 *sql.Stmt
+	deleteSettingStmt                *sql.Stmt
+	deleteTagAliasStmt               *sql.Stmt
+	failInterruptedRollbacksStmt     *sql.Stmt
+	getActiveAPIKeyStmt              *sql.Stmt
+	getActiveSessionUserStmt         *sql.Stmt
+	getLatestScanLogStmt             *sql.Stmt
+	getLatestScanTimeStmt            *sql.Stmt
+	getLatestTaskErrorForObjectStmt  *sql.Stmt
+	getLatestTaskForObjectStmt       *sql.Stmt
+	getObjectStmt                    *sql.Stmt
+	getObjectEnrichmentByTaskStmt    *sql.Stmt
+	getObjectEnrichmentByVersionStmt *sql.Stmt
+	getSettingForUpdateStmt          *sql.Stmt
+	getStaleObjectsStmt              *sql.Stmt
+	getTaskStmt                      *sql.Stmt
+	getTaskBacklogStmt               *sql.Stmt
+	getTaskOutcomeStatsStmt          *sql.Stmt
+	getTaskThroughputStmt            *sql.Stmt
+	getUserStmt                      *sql.Stmt
+	getUserByEmailStmt               *sql.Stmt
+	healthCheckStmt                  *sql.Stmt
+	listAPIKeysStmt                  *sql.Stmt
+	listBlockedTagsStmt              *sql.Stmt
+	listObjectEnrichmentsStmt        *sql.Stmt
+	listObjectTagsStmt               *sql.Stmt
+	listSecretsStmt                  *sql.Stmt
+	listSettingChangesStmt           *sql.Stmt
+	listSettingsStmt                 *sql.Stmt
+	listTagAliasesStmt               *sql.Stmt
+	listUsersStmt                    *sql.Stmt
+	lockObjectForEnrichmentStmt      *sql.Stmt
+	objectsSyncLast60daysStmt        *sql.Stmt
+	requeueFinishedTaskStmt          *sql.Stmt
+	requeueTaskStmt                  *sql.Stmt
+	revokeAPIKeyStmt                 *sql.Stmt
+	revokeSessionStmt                *sql.Stmt
+	rotateAPIKeyStmt                 *sql.Stmt
+	rotateSessionRefreshTokenStmt    *sql.Stmt
+	touchAPIKeyStmt                  *sql.Stmt
+	updateObjectContentHashStmt      *sql.Stmt
+	updateObjectLastSyncedAtStmt     *sql.Stmt
+	updateTaskProcessingStmt         *sql.Stmt
+	updateTaskStatusStmt             *sql.Stmt
+	updateUserLastLoginStmt          *sql.Stmt
+	updateUserRoleStmt               *sql.Stmt
+	upsertSecretStmt                 *sql.Stmt
+	upsertSettingStmt                *sql.Stmt
+	upsertTagAliasStmt               *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                           tx,
-		tx:                           tx,
-		countObjectsStmt:             q.countObjectsStmt,
-		countTasksStmt:               q.countTasksStmt,
-		createObjectStmt:             q.createObjectStmt,
-		createScanLogStmt:            q.createScanLogStmt,
-		createTaskStmt:               q.createTaskStmt,
-		getLatestScanTimeStmt:        q.getLatestScanTimeStmt,
-		getObjectStmt:                q.getObjectStmt,
-		getStaleObjectsStmt:          q.getStaleObjectsStmt,
-		healthCheckStmt:              q.healthCheckStmt,
-		listObjectsStmt:              q.listObjectsStmt,
-		listTasksStmt:                q.listTasksStmt,
-		objectsSyncLast60daysStmt:    q.objectsSyncLast60daysStmt,
-		updateObjectLastSyncedAtStmt: q.updateObjectLastSyncedAtStmt,
-		updateTaskProcessingStmt:     q.updateTaskProcessingStmt,
-		updateTaskStatusStmt:         q.updateTaskStatusStmt,
+		db:                               tx,
+		tx:                               tx,
+		cancelTaskStmt:                   q.cancelTaskStmt,
+		countStaleObjectsStmt:            q.countStaleObjectsStmt,
+		countTasksByStatusStmt:           q.countTasksByStatusStmt,
+		countTasksByStatusAndSourceStmt:  q.countTasksByStatusAndSourceStmt,
+		countTasksByStatusForObjectStmt:  q.countTasksByStatusForObjectStmt,
+		countUsersStmt:                   q.countUsersStmt,
+		createAPIKeyStmt:                 q.createAPIKeyStmt,
+		createAuditEventStmt:             q.createAuditEventStmt,
+		createBlockedTagStmt:             q.createBlockedTagStmt,
+		createObjectStmt:                 q.createObjectStmt,
+		createObjectEnrichmentStmt:       q.createObjectEnrichmentStmt,
+		createObjectTagStmt:              q.createObjectTagStmt,
+		createRollbackTaskStmt:           q.createRollbackTaskStmt,
+		createScanLogStmt:                q.createScanLogStmt,
+		createSessionStmt:                q.createSessionStmt,
+		createSettingChangeStmt:          q.createSettingChangeStmt,
+		createTaskStmt:                   q.createTaskStmt,
+		createUserStmt:                   q.createUserStmt,
+		deleteBlockedTagStmt:             q.deleteBlockedTagStmt,
+		deleteObjectTagStmt:              q.deleteObjectTagStmt,
+		deleteSecretStmt:                 q.deleteSecretStmt,
+		deleteSettingStmt:                q.deleteSettingStmt,
+		deleteTagAliasStmt:               q.deleteTagAliasStmt,
+		failInterruptedRollbacksStmt:     q.failInterruptedRollbacksStmt,
+		getActiveAPIKeyStmt:              q.getActiveAPIKeyStmt,
+		getActiveSessionUserStmt:         q.getActiveSessionUserStmt,
+		getLatestScanLogStmt:             q.getLatestScanLogStmt,
+		getLatestScanTimeStmt:            q.getLatestScanTimeStmt,
+		getLatestTaskErrorForObjectStmt:  q.getLatestTaskErrorForObjectStmt,
+		getLatestTaskForObjectStmt:       q.getLatestTaskForObjectStmt,
+		getObjectStmt:                    q.getObjectStmt,
+		getObjectEnrichmentByTaskStmt:    q.getObjectEnrichmentByTaskStmt,
+		getObjectEnrichmentByVersionStmt: q.getObjectEnrichmentByVersionStmt,
+		getSettingForUpdateStmt:          q.getSettingForUpdateStmt,
+		getStaleObjectsStmt:              q.getStaleObjectsStmt,
+		getTaskStmt:                      q.getTaskStmt,
+		getTaskBacklogStmt:               q.getTaskBacklogStmt,
+		getTaskOutcomeStatsStmt:          q.getTaskOutcomeStatsStmt,
+		getTaskThroughputStmt:            q.getTaskThroughputStmt,
+		getUserStmt:                      q.getUserStmt,
+		getUserByEmailStmt:               q.getUserByEmailStmt,
+		healthCheckStmt:                  q.healthCheckStmt,
+		listAPIKeysStmt:                  q.listAPIKeysStmt,
+		listBlockedTagsStmt:              q.listBlockedTagsStmt,
+		listObjectEnrichmentsStmt:        q.listObjectEnrichmentsStmt,
+		listObjectTagsStmt:               q.listObjectTagsStmt,
+		listSecretsStmt:                  q.listSecretsStmt,
+		listSettingChangesStmt:           q.listSettingChangesStmt,
+		listSettingsStmt:                 q.listSettingsStmt,
+		listTagAliasesStmt:               q.listTagAliasesStmt,
+		listUsersStmt:                    q.listUsersStmt,
+		lockObjectForEnrichmentStmt:      q.lockObjectForEnrichmentStmt,
+		objectsSyncLast60daysStmt:        q.objectsSyncLast60daysStmt,
+		requeueFinishedTaskStmt:          q.requeueFinishedTaskStmt,
+		requeueTaskStmt:                  q.requeueTaskStmt,
+		revokeAPIKeyStmt:                 q.revokeAPIKeyStmt,
+		revokeSessionStmt:                q.revokeSessionStmt,
+		rotateAPIKeyStmt:                 q.rotateAPIKeyStmt,
+		rotateSessionRefreshTokenStmt:    q.rotateSessionRefreshTokenStmt,
+		touchAPIKeyStmt:                  q.touchAPIKeyStmt,
+		updateObjectContentHashStmt:      q.updateObjectContentHashStmt,
+		updateObjectLastSyncedAtStmt:     q.updateObjectLastSyncedAtStmt,
+		updateTaskProcessingStmt:         q.updateTaskProcessingStmt,
+		updateTaskStatusStmt:             q.updateTaskStatusStmt,
+		updateUserLastLoginStmt:          q.updateUserLastLoginStmt,
+		updateUserRoleStmt:               q.updateUserRoleStmt,
+		upsertSecretStmt:                 q.upsertSecretStmt,
+		upsertSettingStmt:                q.upsertSettingStmt,
+		upsertTagAliasStmt:               q.upsertTagAliasStmt,
 	}
 }
@@ -0,0 +1,100 @@
+package database
+
+// Hand-written: exports reuse the search filters but stream every matching row
+// through a server-side cursor instead of paging.
+
+import (
+	"context"
+	"fmt"
+)
+
+// exportBatchSize is how many rows each FETCH pulls from the export cursor
+const exportBatchSize = 500
+
+// buildAll returns every matching row in sort order, without the cursor key column
+func (p pageQuery) buildAll() (string, []interface{}) {
+	dir := "DESC"
+	if p.Asc {
+		dir = "ASC"
+	}
+	key := p.Sort.key()
+	query := fmt.Sprintf("SELECT %s%s%s\nORDER BY %s %s, %s %s",
+		p.Columns, p.From, whereClause(p.Conds), key, dir, p.IDExpr, dir)
+	return query, p.Args
+}
+
+// fetchCursor declares a cursor for query and hands each fetched batch to emit, so
+// memory stays bounded by exportBatchSize however large the result is. Cursors only
+// live inside a transaction, so q must come from WithTx.
+func fetchCursor[T any](ctx context.Context, q *Queries, query string, args []interface{}, scan func(rowScanner, *T) error, emit func([]T) error) error {
+	if q.tx == nil {
+		return fmt.Errorf("export requires a transaction")
+	}
+	if _, err := q.db.ExecContext(ctx, "DECLARE export_cursor NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		return fmt.Errorf("declare export cursor: %w", err)
+	}
+
+	fetch := fmt.Sprintf("FETCH %d FROM export_cursor", exportBatchSize)
+	batch := make([]T, 0, exportBatchSize)
+	for {
+		rows, err := q.db.QueryContext(ctx, fetch)
+		if err != nil {
+			return err
+		}
+		batch = batch[:0]
+		for rows.Next() {
+			var i T
+			if err := scan(rows, &i); err != nil {
+				rows.Close()
+				return err
+			}
+			batch = append(batch, i)
+		}
+		if err := rows.Close(); err != nil {
+			return err
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			if err := emit(batch); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+// ExportTasks calls fn for every task matching f, in the filter's sort order
+func (q *Queries) ExportTasks(ctx context.Context, f TaskFilter, fn func(Task) error) error {
+	query, args := f.pageQuery(Page{}).buildAll()
+	return fetchCursor(ctx, q, query, args, scanTask, func(batch []Task) error {
+		for _, t := range batch {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ExportObjects calls fn for every object matching f, with its latest task attached
+func (q *Queries) ExportObjects(ctx context.Context, f ObjectFilter, fn func(ObjectWithLatestTask) error) error {
+	query, args := f.pageQuery(Page{}).buildAll()
+	scan := func(row rowScanner, i *ObjectWithLatestTask) error {
+		return row.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt, &i.ContentHash)
+	}
+	return fetchCursor(ctx, q, query, args, scan, func(batch []ObjectWithLatestTask) error {
+		if err := q.attachLatestTasks(ctx, batch); err != nil {
+			return err
+		}
+		for _, o := range batch {
+			if err := fn(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -7,15 +7,63 @@ package database
 import (
 	"database/sql"
 	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
+type ApiKey struct {
+	ID         *uuid.UUID   `json:"id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	KeyHash    string       `json:"key_hash"`
+	Scopes     []string     `json:"scopes"`
+	CreatedBy  *uuid.UUID   `json:"created_by"`
+	CreatedAt  sql.NullTime `json:"created_at"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RotatedAt  sql.NullTime `json:"rotated_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+type AuditEvent struct {
+	ID           *uuid.UUID            `json:"id"`
+	OccurredAt   time.Time             `json:"occurred_at"`
+	ActorType    string                `json:"actor_type"`
+	ActorID      *uuid.UUID            `json:"actor_id"`
+	ActorName    sql.NullString        `json:"actor_name"`
+	Action       string                `json:"action"`
+	TargetType   sql.NullString        `json:"target_type"`
+	TargetID     sql.NullString        `json:"target_id"`
+	RequestID    sql.NullString        `json:"request_id"`
+	Method       sql.NullString        `json:"method"`
+	Path         sql.NullString        `json:"path"`
+	Payload      pqtype.NullRawMessage `json:"payload"`
+	Ip           sql.NullString        `json:"ip"`
+	ForwardedFor sql.NullString        `json:"forwarded_for"`
+	Result       string                `json:"result"`
+	StatusCode   sql.NullInt32         `json:"status_code"`
+	Error        sql.NullString        `json:"error"`
+}
+
 type Object struct {
-	ID           *uuid.UUID   `json:"id"`
-	CreatedAt    sql.NullTime `json:"created_at"`
-	LastSyncedAt sql.NullTime `json:"last_synced_at"`
+	ID           *uuid.UUID     `json:"id"`
+	CreatedAt    sql.NullTime   `json:"created_at"`
+	LastSyncedAt sql.NullTime   `json:"last_synced_at"`
+	ContentHash  sql.NullString `json:"content_hash"`
+}
+
+type ObjectEnrichment struct {
+	ID             *uuid.UUID      `json:"id"`
+	ObjectID       *uuid.UUID      `json:"object_id"`
+	Version        int32           `json:"version"`
+	TaskID         *uuid.UUID      `json:"task_id"`
+	TypeValues     json.RawMessage `json:"type_values"`
+	Tags           []string        `json:"tags"`
+	ContentHash    string          `json:"content_hash"`
+	MappingVersion sql.NullString  `json:"mapping_version"`
+	CreatedAt      sql.NullTime    `json:"created_at"`
 }
 
 type ObjectScanLog struct {
@@ -24,14 +72,79 @@ type ObjectScanLog struct {
 	CreatedAt sql.NullTime `json:"created_at"`
 }
 
+type ObjectTag struct {
+	ObjectID  *uuid.UUID   `json:"object_id"`
+	Tag       string       `json:"tag"`
+	AppliedAt sql.NullTime `json:"applied_at"`
+}
+
+type Secret struct {
+	Name       string         `json:"name"`
+	Ciphertext []byte         `json:"ciphertext"`
+	Nonce      []byte         `json:"nonce"`
+	Version    int32          `json:"version"`
+	UpdatedBy  sql.NullString `json:"updated_by"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+type Session struct {
+	ID               *uuid.UUID     `json:"id"`
+	UserID           *uuid.UUID     `json:"user_id"`
+	RefreshTokenHash string         `json:"refresh_token_hash"`
+	UserAgent        sql.NullString `json:"user_agent"`
+	CreatedAt        sql.NullTime   `json:"created_at"`
+	LastUsedAt       sql.NullTime   `json:"last_used_at"`
+	ExpiresAt        time.Time      `json:"expires_at"`
+	RevokedAt        sql.NullTime   `json:"revoked_at"`
+}
+
+type Setting struct {
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	UpdatedBy *uuid.UUID      `json:"updated_by"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type SettingChange struct {
+	ID        *uuid.UUID            `json:"id"`
+	Key       string                `json:"key"`
+	OldValue  pqtype.NullRawMessage `json:"old_value"`
+	NewValue  pqtype.NullRawMessage `json:"new_value"`
+	ChangedBy *uuid.UUID            `json:"changed_by"`
+	ChangedAt time.Time             `json:"changed_at"`
+}
+
+type TagAlias struct {
+	Alias     string       `json:"alias"`
+	Tag       string       `json:"tag"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
+type TagBlocklist struct {
+	Tag       string       `json:"tag"`
+	CreatedAt sql.NullTime `json:"created_at"`
+}
+
 type Task struct {
-	ID          *uuid.UUID            `json:"id"`
-	ObjectID    *uuid.UUID            `json:"object_id"`
-	Status      string                `json:"status"`
-	Input       json.RawMessage       `json:"input"`
-	Output      pqtype.NullRawMessage `json:"output"`
-	Error       sql.NullString        `json:"error"`
-	CreatedAt   sql.NullTime          `json:"created_at"`
-	StartedAt   sql.NullTime          `json:"started_at"`
-	CompletedAt sql.NullTime          `json:"completed_at"`
+	ID             *uuid.UUID            `json:"id"`
+	ObjectID       *uuid.UUID            `json:"object_id"`
+	Status         string                `json:"status"`
+	Input          json.RawMessage       `json:"input"`
+	Output         pqtype.NullRawMessage `json:"output"`
+	Error          sql.NullString        `json:"error"`
+	CreatedAt      sql.NullTime          `json:"created_at"`
+	StartedAt      sql.NullTime          `json:"started_at"`
+	CompletedAt    sql.NullTime          `json:"completed_at"`
+	MappingVersion sql.NullString        `json:"mapping_version"`
+	Source         string                `json:"source"`
+}
+
+type User struct {
+	ID           *uuid.UUID   `json:"id"`
+	Email        string       `json:"email"`
+	PasswordHash string       `json:"password_hash"`
+	Disabled     bool         `json:"disabled"`
+	CreatedAt    sql.NullTime `json:"created_at"`
+	LastLoginAt  sql.NullTime `json:"last_login_at"`
+	Role         string       `json:"role"`
 }
@@ -0,0 +1,195 @@
+package database
+
+// Hand-written: sqlc cannot express the optional filters and selectable sort
+// order used by the object listing, so the SQL is assembled here instead.
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ObjectSortFields maps the sort names accepted by the API to SQL expressions.
+// NULLs (never-synced objects, objects without tasks) sort as the oldest values.
+var ObjectSortFields = map[string]sortColumn{
+	"last_synced_at":  {Expr: "o.last_synced_at", Type: "timestamptz"},
+	"created_at":      {Expr: "o.created_at", Type: "timestamptz"},
+	"last_attempt_at": {Expr: "lt.created_at", Type: "timestamptz"},
+}
+
+const DefaultObjectSort = "last_synced_at"
+
+// ObjectFilter narrows SearchObjects; the zero value lists every object
+type ObjectFilter struct {
+	NeverSynced       bool
+	StaleSince        *time.Time
+	FailedLastAttempt bool
+	CreatedAfter      *time.Time
+	CreatedBefore     *time.Time
+	SortBy            string
+	SortAsc           bool
+}
+
+// ObjectWithLatestTask is an object row plus its most recent task, if any
+type ObjectWithLatestTask struct {
+	Object
+	LatestTask *Task `json:"latest_task"`
+}
+
+const objectSearchFrom = `
+FROM objects o
+LEFT JOIN LATERAL (
+  SELECT t.status, t.created_at
+  FROM tasks t
+  WHERE t.object_id = o.id
+  ORDER BY t.created_at DESC
+  LIMIT 1
+) lt ON true`
+
+func (f ObjectFilter) where() ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if f.NeverSynced {
+		conds = append(conds, "o.last_synced_at IS NULL")
+	}
+	if f.StaleSince != nil {
+		conds = append(conds, "o.last_synced_at < "+arg(*f.StaleSince))
+	}
+	if f.FailedLastAttempt {
+		conds = append(conds, "lt.status = 'failed'")
+	}
+	if f.CreatedAfter != nil {
+		conds = append(conds, "o.created_at >= "+arg(*f.CreatedAfter))
+	}
+	if f.CreatedBefore != nil {
+		conds = append(conds, "o.created_at < "+arg(*f.CreatedBefore))
+	}
+	return conds, args
+}
+
+func (f ObjectFilter) pageQuery(p Page) pageQuery {
+	sortBy := f.SortBy
+	if _, ok := ObjectSortFields[sortBy]; !ok {
+		sortBy = DefaultObjectSort
+	}
+	conds, args := f.where()
+	return pageQuery{
+		Columns: "o.id, o.created_at, o.last_synced_at, o.content_hash",
+		From:    objectSearchFrom,
+		Conds:   conds,
+		Args:    args,
+		Sort:    ObjectSortFields[sortBy],
+		SortBy:  sortBy,
+		Asc:     f.SortAsc,
+		IDExpr:  "o.id",
+		Page:    p,
+	}
+}
+
+func (q *Queries) SearchObjects(ctx context.Context, f ObjectFilter, p Page) ([]ObjectWithLatestTask, PageCursors, error) {
+	page := f.pageQuery(p)
+	query, args := page.build()
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, PageCursors{}, err
+	}
+	defer rows.Close()
+	var items []ObjectWithLatestTask
+	var keys []pageKey
+	for rows.Next() {
+		var i ObjectWithLatestTask
+		var key string
+		if err := rows.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt, &i.ContentHash, &key); err != nil {
+			return nil, PageCursors{}, err
+		}
+		items = append(items, i)
+		keys = append(keys, pageKey{Key: key, ID: *i.ID})
+	}
+	if err := rows.Close(); err != nil {
+		return nil, PageCursors{}, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, PageCursors{}, err
+	}
+
+	items, cursors := finishPage(page, items, keys)
+	if err := q.attachLatestTasks(ctx, items); err != nil {
+		return nil, PageCursors{}, err
+	}
+	return items, cursors, nil
+}
+
+func (q *Queries) CountSearchObjects(ctx context.Context, f ObjectFilter, mode CountMode) (*int64, error) {
+	conds, args := f.where()
+	return q.count(ctx, mode, objectSearchFrom, conds, args)
+}
+
+// attachLatestTasks loads the newest task of every object on the page in one query
+func (q *Queries) attachLatestTasks(ctx context.Context, items []ObjectWithLatestTask) error {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID.String()
+	}
+
+	rows, err := q.db.QueryContext(ctx, `SELECT DISTINCT ON (object_id) `+taskColumns+`
+FROM tasks
+WHERE object_id = ANY($1::uuid[])
+ORDER BY object_id, created_at DESC`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	latest := make(map[uuid.UUID]*Task, len(items))
+	for rows.Next() {
+		var t Task
+		if err := scanTask(rows, &t); err != nil {
+			return err
+		}
+		latest[*t.ObjectID] = &t
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range items {
+		items[i].LatestTask = latest[*items[i].ID]
+	}
+	return nil
+}
+
+const taskColumns = "id, object_id, status, input, output, error, created_at, started_at, completed_at, mapping_version, source"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTask(row rowScanner, t *Task) error {
+	return row.Scan(
+		&t.ID,
+		&t.ObjectID,
+		&t.Status,
+		&t.Input,
+		&t.Output,
+		&t.Error,
+		&t.CreatedAt,
+		&t.StartedAt,
+		&t.CompletedAt,
+		&t.MappingVersion,
+		&t.Source,
+	)
+}
@@ -0,0 +1,199 @@
+package database
+
+// Hand-written: keyset (cursor) and offset pagination shared by the search
+// queries in this package.
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Cursor is the decoded form of the opaque next/prev cursors handed to clients.
+// It carries the sort it was issued for so follow-up pages stay consistent.
+type Cursor struct {
+	Sort string    `json:"s"`
+	Asc  bool      `json:"a,omitempty"`
+	Key  string    `json:"k"`
+	ID   uuid.UUID `json:"i"`
+	Prev bool      `json:"p,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var c Cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Key == "" {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &c, nil
+}
+
+// Page selects one page of a search. Cursor takes precedence over Offset.
+type Page struct {
+	Limit  int32
+	Offset int32
+	Cursor *Cursor
+}
+
+// PageCursors are the cursors for the pages either side of the one returned
+type PageCursors struct {
+	Next string `json:"next_cursor,omitempty"`
+	Prev string `json:"prev_cursor,omitempty"`
+}
+
+// CountMode controls how the total for a search is computed
+type CountMode string
+
+const (
+	CountExact    CountMode = "exact"
+	CountEstimate CountMode = "estimate"
+	CountNone     CountMode = "none"
+)
+
+// sortColumn is a sortable SQL expression. Type is the cast used for cursor keys and for the
+// -infinity NULL sentinel, which keeps row comparisons total: NULLs sort as the smallest value,
+// last when descending and first when ascending. Using one sentinel for both directions lets a
+// single expression index on the key serve either order.
+type sortColumn struct {
+	Expr string
+	Type string
+}
+
+func (c sortColumn) key() string {
+	return fmt.Sprintf("COALESCE(%s, '-infinity'::%s)", c.Expr, c.Type)
+}
+
+// pageQuery is the shared shape of a paginated search
+type pageQuery struct {
+	Columns string
+	From    string
+	Conds   []string
+	Args    []interface{}
+	Sort    sortColumn
+	SortBy  string
+	Asc     bool
+	IDExpr  string
+	Page    Page
+}
+
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return "\nWHERE " + strings.Join(conds, "\nAND ")
+}
+
+// build returns the page query; every row ends with the sort key as text. One extra
+// row is requested so callers can tell whether another page exists.
+func (p pageQuery) build() (string, []interface{}) {
+	args := append([]interface{}(nil), p.Args...)
+	conds := append([]string(nil), p.Conds...)
+	key := p.Sort.key()
+
+	// Walking backwards flips the comparison and the order; finishPage restores it
+	asc := p.Asc
+	if p.Page.Cursor != nil && p.Page.Cursor.Prev {
+		asc = !asc
+	}
+	op, dir := "<", "DESC"
+	if asc {
+		op, dir = ">", "ASC"
+	}
+
+	if p.Page.Cursor != nil {
+		args = append(args, p.Page.Cursor.Key, p.Page.Cursor.ID)
+		conds = append(conds, fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d::uuid)", key, p.IDExpr, op, len(args)-1, p.Sort.Type, len(args)))
+	}
+
+	query := fmt.Sprintf("SELECT %s, (%s)::text%s%s\nORDER BY %s %s, %s %s",
+		p.Columns, key, p.From, whereClause(conds), key, dir, p.IDExpr, dir)
+	args = append(args, p.Page.Limit+1)
+	query += fmt.Sprintf("\nLIMIT $%d", len(args))
+	if p.Page.Cursor == nil && p.Page.Offset > 0 {
+		args = append(args, p.Page.Offset)
+		query += fmt.Sprintf("\nOFFSET $%d", len(args))
+	}
+	return query, args
+}
+
+type pageKey struct {
+	Key string
+	ID  uuid.UUID
+}
+
+// finishPage trims the look-ahead row, restores order for backward pages and issues cursors
+func finishPage[T any](p pageQuery, items []T, keys []pageKey) ([]T, PageCursors) {
+	hasMore := len(items) > int(p.Page.Limit)
+	if hasMore {
+		items = items[:p.Page.Limit]
+		keys = keys[:p.Page.Limit]
+	}
+
+	backward := p.Page.Cursor != nil && p.Page.Cursor.Prev
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+
+	hasNext, hasPrev := hasMore, p.Page.Cursor != nil || p.Page.Offset > 0
+	if backward {
+		hasNext, hasPrev = true, hasMore
+	}
+
+	var cursors PageCursors
+	if len(keys) == 0 {
+		return items, cursors
+	}
+	if hasNext {
+		last := keys[len(keys)-1]
+		cursors.Next = Cursor{Sort: p.SortBy, Asc: p.Asc, Key: last.Key, ID: last.ID}.Encode()
+	}
+	if hasPrev {
+		first := keys[0]
+		cursors.Prev = Cursor{Sort: p.SortBy, Asc: p.Asc, Key: first.Key, ID: first.ID, Prev: true}.Encode()
+	}
+	return items, cursors
+}
+
+// count returns the exact or planner-estimated number of rows matching from/conds, or nil for CountNone
+func (q *Queries) count(ctx context.Context, mode CountMode, from string, conds []string, args []interface{}) (*int64, error) {
+	switch mode {
+	case CountNone:
+		return nil, nil
+	case CountEstimate:
+		var plan []struct {
+			Plan struct {
+				Rows float64 `json:"Plan Rows"`
+			} `json:"Plan"`
+		}
+		var raw []byte
+		if err := q.db.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) SELECT 1"+from+whereClause(conds), args...).Scan(&raw); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &plan); err != nil || len(plan) == 0 {
+			return nil, fmt.Errorf("parse count estimate: %v", err)
+		}
+		estimate := int64(plan[0].Plan.Rows)
+		return &estimate, nil
+	default:
+		var total int64
+		if err := q.db.QueryRowContext(ctx, "SELECT COUNT(*)"+from+whereClause(conds), args...).Scan(&total); err != nil {
+			return nil, err
+		}
+		return &total, nil
+	}
+}
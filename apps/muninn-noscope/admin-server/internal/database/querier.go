@@ -12,21 +12,75 @@ import (
 )
 
 type Querier interface {
-	CountObjects(ctx context.Context) (int64, error)
-	CountTasks(ctx context.Context, arg CountTasksParams) (int64, error)
+	CancelTask(ctx context.Context, id *uuid.UUID) (Task, error)
+	CountStaleObjects(ctx context.Context, lastSyncedAt sql.NullTime) (int64, error)
+	CountTasksByStatus(ctx context.Context) ([]CountTasksByStatusRow, error)
+	CountTasksByStatusAndSource(ctx context.Context, arg CountTasksByStatusAndSourceParams) ([]CountTasksByStatusAndSourceRow, error)
+	CountTasksByStatusForObject(ctx context.Context, objectID *uuid.UUID) ([]CountTasksByStatusForObjectRow, error)
+	CountUsers(ctx context.Context) (int64, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateBlockedTag(ctx context.Context, tag string) (TagBlocklist, error)
 	CreateObject(ctx context.Context, id *uuid.UUID) (Object, error)
+	CreateObjectEnrichment(ctx context.Context, arg CreateObjectEnrichmentParams) (ObjectEnrichment, error)
+	CreateObjectTag(ctx context.Context, arg CreateObjectTagParams) error
+	CreateRollbackTask(ctx context.Context, arg CreateRollbackTaskParams) (CreateRollbackTaskRow, error)
 	CreateScanLog(ctx context.Context, latest sql.NullTime) error
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSettingChange(ctx context.Context, arg CreateSettingChangeParams) (SettingChange, error)
 	CreateTask(ctx context.Context, arg CreateTaskParams) (CreateTaskRow, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteBlockedTag(ctx context.Context, tag string) (int64, error)
+	DeleteObjectTag(ctx context.Context, arg DeleteObjectTagParams) error
+	DeleteSecret(ctx context.Context, name string) (int64, error)
+	DeleteSetting(ctx context.Context, key string) (Setting, error)
+	DeleteTagAlias(ctx context.Context, alias string) (int64, error)
+	FailInterruptedRollbacks(ctx context.Context, arg FailInterruptedRollbacksParams) (int64, error)
+	GetActiveAPIKey(ctx context.Context, keyHash string) (ApiKey, error)
+	GetActiveSessionUser(ctx context.Context, id *uuid.UUID) (GetActiveSessionUserRow, error)
+	GetLatestScanLog(ctx context.Context) (ObjectScanLog, error)
 	GetLatestScanTime(ctx context.Context) (sql.NullTime, error)
+	GetLatestTaskErrorForObject(ctx context.Context, objectID *uuid.UUID) (GetLatestTaskErrorForObjectRow, error)
+	GetLatestTaskForObject(ctx context.Context, objectID *uuid.UUID) (Task, error)
 	GetObject(ctx context.Context, id *uuid.UUID) (Object, error)
+	GetObjectEnrichmentByTask(ctx context.Context, arg GetObjectEnrichmentByTaskParams) (ObjectEnrichment, error)
+	GetObjectEnrichmentByVersion(ctx context.Context, arg GetObjectEnrichmentByVersionParams) (ObjectEnrichment, error)
+	GetSettingForUpdate(ctx context.Context, key string) (Setting, error)
 	GetStaleObjects(ctx context.Context, lastSyncedAt sql.NullTime) ([]*uuid.UUID, error)
+	GetTask(ctx context.Context, id *uuid.UUID) (Task, error)
+	GetTaskBacklog(ctx context.Context) (GetTaskBacklogRow, error)
+	GetTaskOutcomeStats(ctx context.Context, arg GetTaskOutcomeStatsParams) (GetTaskOutcomeStatsRow, error)
+	GetTaskThroughput(ctx context.Context, arg GetTaskThroughputParams) ([]GetTaskThroughputRow, error)
+	GetUser(ctx context.Context, id *uuid.UUID) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
 	HealthCheck(ctx context.Context) (int32, error)
-	ListObjects(ctx context.Context, arg ListObjectsParams) ([]Object, error)
-	ListTasks(ctx context.Context, arg ListTasksParams) ([]Task, error)
+	ListAPIKeys(ctx context.Context) ([]ApiKey, error)
+	ListBlockedTags(ctx context.Context) ([]TagBlocklist, error)
+	ListObjectEnrichments(ctx context.Context, objectID *uuid.UUID) ([]ObjectEnrichment, error)
+	ListObjectTags(ctx context.Context, objectID *uuid.UUID) ([]string, error)
+	ListSecrets(ctx context.Context) ([]Secret, error)
+	ListSettingChanges(ctx context.Context, arg ListSettingChangesParams) ([]SettingChange, error)
+	ListSettings(ctx context.Context) ([]Setting, error)
+	ListTagAliases(ctx context.Context) ([]TagAlias, error)
+	ListUsers(ctx context.Context) ([]User, error)
+	LockObjectForEnrichment(ctx context.Context, id *uuid.UUID) (*uuid.UUID, error)
 	ObjectsSyncLast60days(ctx context.Context) ([]Object, error)
+	RequeueFinishedTask(ctx context.Context, id *uuid.UUID) (Task, error)
+	RequeueTask(ctx context.Context, id *uuid.UUID) error
+	RevokeAPIKey(ctx context.Context, id *uuid.UUID) (ApiKey, error)
+	RevokeSession(ctx context.Context, id *uuid.UUID) error
+	RotateAPIKey(ctx context.Context, arg RotateAPIKeyParams) (ApiKey, error)
+	RotateSessionRefreshToken(ctx context.Context, arg RotateSessionRefreshTokenParams) (Session, error)
+	TouchAPIKey(ctx context.Context, id *uuid.UUID) error
+	UpdateObjectContentHash(ctx context.Context, arg UpdateObjectContentHashParams) error
 	UpdateObjectLastSyncedAt(ctx context.Context, arg UpdateObjectLastSyncedAtParams) (Object, error)
 	UpdateTaskProcessing(ctx context.Context, startedAt sql.NullTime) (UpdateTaskProcessingRow, error)
 	UpdateTaskStatus(ctx context.Context, arg UpdateTaskStatusParams) error
+	UpdateUserLastLogin(ctx context.Context, id *uuid.UUID) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpsertSecret(ctx context.Context, arg UpsertSecretParams) (Secret, error)
+	UpsertSetting(ctx context.Context, arg UpsertSettingParams) (Setting, error)
+	UpsertTagAlias(ctx context.Context, arg UpsertTagAliasParams) (TagAlias, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -12,28 +12,16 @@ import (
 	"github.com/google/uuid"
 )
 
-const countObjects = `-- name: CountObjects :one
-SELECT COUNT(*)
-FROM objects
-`
-
-func (q *Queries) CountObjects(ctx context.Context) (int64, error) {
-	row := q.queryRow(ctx, q.countObjectsStmt, countObjects)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
 const createObject = `-- name: CreateObject :one
 INSERT INTO objects (id)
 VALUES ($1)
-RETURNING id, created_at, last_synced_at
+RETURNING id, created_at, last_synced_at, content_hash
 `
 
 func (q *Queries) CreateObject(ctx context.Context, id *uuid.UUID) (Object, error) {
 	row := q.queryRow(ctx, q.createObjectStmt, createObject, id)
 	var i Object
-	err := row.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt)
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt, &i.ContentHash)
 	return i, err
 }
 
@@ -62,14 +50,14 @@ func (q *Queries) GetLatestScanTime(ctx context.Context) (sql.NullTime, error) {
 }
 
 const getObject = `-- name: GetObject :one
-SELECT id, created_at, last_synced_at FROM objects
+SELECT id, created_at, last_synced_at, content_hash FROM objects
 WHERE id = $1
 `
 
 func (q *Queries) GetObject(ctx context.Context, id *uuid.UUID) (Object, error) {
 	row := q.queryRow(ctx, q.getObjectStmt, getObject, id)
 	var i Object
-	err := row.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt)
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt, &i.ContentHash)
 	return i, err
 }
 
@@ -84,44 +72,8 @@ func (q *Queries) HealthCheck(ctx context.Context) (int32, error) {
 	return column_1, err
 }
 
-const listObjects = `-- name: ListObjects :many
-SELECT id, created_at, last_synced_at
-FROM objects
-ORDER BY last_synced_at DESC NULLS LAST
-LIMIT $1
-OFFSET $2
-`
-
-type ListObjectsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
-}
-
-func (q *Queries) ListObjects(ctx context.Context, arg ListObjectsParams) ([]Object, error) {
-	rows, err := q.query(ctx, q.listObjectsStmt, listObjects, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []Object
-	for rows.Next() {
-		var i Object
-		if err := rows.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
 const objectsSyncLast60days = `-- name: ObjectsSyncLast60days :many
-SELECT id, created_at, last_synced_at
+SELECT id, created_at, last_synced_at, content_hash
 FROM objects
 WHERE last_synced_at > NOW() - INTERVAL '60 days'
 `
@@ -135,7 +87,7 @@ func (q *Queries) ObjectsSyncLast60days(ctx context.Context) ([]Object, error) {
 	var items []Object
 	for rows.Next() {
 		var i Object
-		if err := rows.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt); err != nil {
+		if err := rows.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt, &i.ContentHash); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -149,11 +101,27 @@ func (q *Queries) ObjectsSyncLast60days(ctx context.Context) ([]Object, error) {
 	return items, nil
 }
 
+const updateObjectContentHash = `-- name: UpdateObjectContentHash :exec
+UPDATE objects
+SET content_hash = $2
+WHERE id = $1
+`
+
+type UpdateObjectContentHashParams struct {
+	ID          *uuid.UUID     `json:"id"`
+	ContentHash sql.NullString `json:"content_hash"`
+}
+
+func (q *Queries) UpdateObjectContentHash(ctx context.Context, arg UpdateObjectContentHashParams) error {
+	_, err := q.exec(ctx, q.updateObjectContentHashStmt, updateObjectContentHash, arg.ID, arg.ContentHash)
+	return err
+}
+
 const updateObjectLastSyncedAt = `-- name: UpdateObjectLastSyncedAt :one
 UPDATE objects
 SET last_synced_at = $2
 WHERE id = $1
-RETURNING id, created_at, last_synced_at
+RETURNING id, created_at, last_synced_at, content_hash
 `
 
 type UpdateObjectLastSyncedAtParams struct {
@@ -164,6 +132,6 @@ type UpdateObjectLastSyncedAtParams struct {
 func (q *Queries) UpdateObjectLastSyncedAt(ctx context.Context, arg UpdateObjectLastSyncedAtParams) (Object, error) {
 	row := q.queryRow(ctx, q.updateObjectLastSyncedAtStmt, updateObjectLastSyncedAt, arg.ID, arg.LastSyncedAt)
 	var i Object
-	err := row.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt)
+	err := row.Scan(&i.ID, &i.CreatedAt, &i.LastSyncedAt, &i.ContentHash)
 	return i, err
 }
@@ -3,12 +3,14 @@ WITH new_task AS (
   INSERT INTO tasks (
     object_id,
     status,
-    input
+    input,
+    source
   )
   SELECT 
     $1,
     'pending',
-    $2
+    $2,
+    $3
   WHERE NOT EXISTS (
     SELECT 1 
     FROM tasks 
@@ -17,4 +19,60 @@ WITH new_task AS (
   )
   RETURNING *
 )
-SELECT * FROM new_task;
\ No newline at end of file
+SELECT * FROM new_task;
+
+-- name: CreateRollbackTask :one
+INSERT INTO tasks (
+  object_id,
+  status,
+  input,
+  source,
+  started_at
+)
+VALUES (
+  $1,
+  'processing',
+  $2,
+  'rollback',
+  $3
+)
+RETURNING id, object_id, input;
+
+-- name: FailInterruptedRollbacks :execrows
+UPDATE tasks
+SET status = 'failed',
+  error = 'rollback interrupted',
+  completed_at = NOW()
+WHERE object_id = $1
+  AND source = 'rollback'
+  AND status = 'processing'
+  AND started_at < $2;
+
+-- name: CancelTask :one
+UPDATE tasks
+SET status = 'failed',
+  error = 'cancelled',
+  completed_at = NOW()
+WHERE id = $1
+  AND status = 'pending'
+RETURNING *;
+
+-- name: RequeueFinishedTask :one
+-- Rollback tasks are not requeued: the worker would treat their input as an enrichment
+UPDATE tasks
+SET status = 'pending',
+  output = NULL,
+  error = NULL,
+  started_at = NULL,
+  completed_at = NULL,
+  mapping_version = NULL
+WHERE id = $1
+  AND status IN ('failed', 'completed', 'unchanged')
+  AND source <> 'rollback'
+  AND NOT EXISTS (
+    SELECT 1
+    FROM tasks pending
+    WHERE pending.object_id = tasks.object_id
+    AND pending.status = 'pending'
+  )
+RETURNING *;
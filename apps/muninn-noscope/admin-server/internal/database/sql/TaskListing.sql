@@ -1,16 +1,26 @@
--- name: ListTasks :many
+-- name: GetTask :one
 SELECT *
 FROM tasks
-WHERE 
-    ($1::uuid IS NULL OR object_id = $1::uuid) AND
-    ($2::text = '' OR status = $2::text)
+WHERE id = $1;
+
+-- name: GetLatestTaskForObject :one
+SELECT *
+FROM tasks
+WHERE object_id = $1
 ORDER BY created_at DESC
-LIMIT $3
-OFFSET $4;
+LIMIT 1;
+
+-- name: GetLatestTaskErrorForObject :one
+SELECT error, completed_at
+FROM tasks
+WHERE object_id = $1
+AND error IS NOT NULL
+ORDER BY completed_at DESC NULLS LAST
+LIMIT 1;
 
--- name: CountTasks :one
-SELECT COUNT(*)
+-- name: CountTasksByStatusForObject :many
+SELECT status, COUNT(*)
 FROM tasks
-WHERE 
-    ($1::uuid IS NULL OR object_id = $1::uuid) AND
-    ($2::text = '' OR status = $2::text);
\ No newline at end of file
+WHERE object_id = $1
+GROUP BY status
+ORDER BY status;
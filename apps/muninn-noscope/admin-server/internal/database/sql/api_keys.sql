@@ -0,0 +1,39 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (name, prefix, key_hash, scopes, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListAPIKeys :many
+SELECT *
+FROM api_keys
+ORDER BY created_at DESC;
+
+-- name: GetActiveAPIKey :one
+SELECT *
+FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL
+  AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: TouchAPIKey :exec
+-- last_used_at is kept to the minute so busy keys do not write on every request
+UPDATE api_keys
+SET last_used_at = NOW()
+WHERE id = $1
+  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: RotateAPIKey :one
+UPDATE api_keys
+SET prefix = $2,
+    key_hash = $3,
+    rotated_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeAPIKey :one
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL
+RETURNING *;
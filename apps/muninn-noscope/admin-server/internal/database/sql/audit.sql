@@ -0,0 +1,19 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO audit_events (
+  actor_type,
+  actor_id,
+  actor_name,
+  action,
+  target_type,
+  target_id,
+  request_id,
+  method,
+  path,
+  payload,
+  ip,
+  forwarded_for,
+  result,
+  status_code,
+  error
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
@@ -0,0 +1,63 @@
+-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users;
+
+-- name: CreateUser :one
+INSERT INTO users (email, password_hash, role)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetUser :one
+SELECT *
+FROM users
+WHERE id = $1;
+
+-- name: GetUserByEmail :one
+SELECT *
+FROM users
+WHERE email = $1;
+
+-- name: ListUsers :many
+SELECT *
+FROM users
+ORDER BY email;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserLastLogin :exec
+UPDATE users
+SET last_login_at = NOW()
+WHERE id = $1;
+
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, refresh_token_hash, user_agent, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetActiveSessionUser :one
+SELECT u.id, u.email, u.role, u.disabled
+FROM sessions s
+JOIN users u ON u.id = s.user_id
+WHERE s.id = $1
+  AND s.revoked_at IS NULL
+  AND s.expires_at > NOW();
+
+-- name: RotateSessionRefreshToken :one
+UPDATE sessions
+SET refresh_token_hash = @new_hash,
+    expires_at = @expires_at,
+    last_used_at = NOW()
+WHERE refresh_token_hash = @old_hash
+  AND revoked_at IS NULL
+  AND expires_at > NOW()
+RETURNING *;
+
+-- name: RevokeSession :exec
+UPDATE sessions
+SET revoked_at = NOW()
+WHERE id = $1
+  AND revoked_at IS NULL;
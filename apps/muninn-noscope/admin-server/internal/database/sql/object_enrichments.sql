@@ -0,0 +1,45 @@
+-- name: CreateObjectEnrichment :one
+INSERT INTO object_enrichments (
+  object_id,
+  version,
+  task_id,
+  type_values,
+  tags,
+  content_hash,
+  mapping_version
+)
+SELECT
+  $1,
+  COALESCE(MAX(version), 0) + 1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6
+FROM object_enrichments
+WHERE object_id = $1
+RETURNING *;
+
+-- name: ListObjectEnrichments :many
+SELECT *
+FROM object_enrichments
+WHERE object_id = $1
+ORDER BY version;
+
+-- name: GetObjectEnrichmentByVersion :one
+SELECT *
+FROM object_enrichments
+WHERE object_id = $1
+AND version = $2;
+
+-- name: GetObjectEnrichmentByTask :one
+SELECT *
+FROM object_enrichments
+WHERE object_id = $1
+AND task_id = $2;
+
+-- name: LockObjectForEnrichment :one
+SELECT id
+FROM objects
+WHERE id = $1
+FOR UPDATE;
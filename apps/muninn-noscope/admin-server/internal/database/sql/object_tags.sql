@@ -0,0 +1,15 @@
+-- name: ListObjectTags :many
+SELECT tag
+FROM object_tags
+WHERE object_id = $1
+ORDER BY tag;
+
+-- name: CreateObjectTag :exec
+INSERT INTO object_tags (object_id, tag)
+VALUES ($1, $2)
+ON CONFLICT (object_id, tag) DO NOTHING;
+
+-- name: DeleteObjectTag :exec
+DELETE FROM object_tags
+WHERE object_id = $1
+AND tag = $2;
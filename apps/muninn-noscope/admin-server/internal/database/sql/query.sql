@@ -7,17 +7,6 @@ RETURNING *;
 SELECT * FROM objects
 WHERE id = $1;
 
--- name: ListObjects :many
-SELECT *
-FROM objects
-ORDER BY last_synced_at DESC NULLS LAST
-LIMIT $1
-OFFSET $2;
-
--- name: CountObjects :one
-SELECT COUNT(*)
-FROM objects;
-
 -- name: UpdateObjectLastSyncedAt :one
 UPDATE objects
 SET last_synced_at = $2
@@ -40,4 +29,9 @@ INSERT INTO object_scan_logs (latest)
 VALUES ($1);
 
 -- name: HealthCheck :one
-Select 1;
\ No newline at end of file
+Select 1;
+
+-- name: UpdateObjectContentHash :exec
+UPDATE objects
+SET content_hash = $2
+WHERE id = $1;
@@ -0,0 +1,19 @@
+-- name: ListSecrets :many
+SELECT *
+FROM secrets
+ORDER BY name;
+
+-- name: UpsertSecret :one
+INSERT INTO secrets (name, ciphertext, nonce, updated_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (name) DO UPDATE
+SET ciphertext = EXCLUDED.ciphertext,
+    nonce = EXCLUDED.nonce,
+    version = secrets.version + 1,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteSecret :execrows
+DELETE FROM secrets
+WHERE name = $1;
@@ -0,0 +1,36 @@
+-- name: ListSettings :many
+SELECT *
+FROM settings
+ORDER BY key;
+
+-- name: GetSettingForUpdate :one
+SELECT *
+FROM settings
+WHERE key = $1
+FOR UPDATE;
+
+-- name: UpsertSetting :one
+INSERT INTO settings (key, value, updated_by)
+VALUES ($1, $2, $3)
+ON CONFLICT (key) DO UPDATE
+SET value = EXCLUDED.value,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteSetting :one
+DELETE FROM settings
+WHERE key = $1
+RETURNING *;
+
+-- name: CreateSettingChange :one
+INSERT INTO setting_changes (key, old_value, new_value, changed_by)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListSettingChanges :many
+SELECT *
+FROM setting_changes
+WHERE (@key::text = '' OR key = @key::text)
+ORDER BY changed_at DESC
+LIMIT @row_limit;
@@ -0,0 +1,50 @@
+-- name: CountTasksByStatusAndSource :many
+SELECT status, source, COUNT(*) AS count
+FROM tasks
+WHERE created_at >= @window_start::timestamptz
+AND created_at < @window_end::timestamptz
+GROUP BY status, source
+ORDER BY status, source;
+
+-- name: CountTasksByStatus :many
+SELECT status, COUNT(*) AS count
+FROM tasks
+GROUP BY status
+ORDER BY status;
+
+-- name: GetTaskThroughput :many
+SELECT date_trunc(@bucket::text, completed_at)::timestamptz AS bucket,
+  COUNT(*) FILTER (WHERE status IN ('completed', 'unchanged')) AS succeeded,
+  COUNT(*) FILTER (WHERE status = 'failed') AS failed
+FROM tasks
+WHERE completed_at >= @window_start::timestamptz
+AND completed_at < @window_end::timestamptz
+GROUP BY 1
+ORDER BY 1;
+
+-- name: GetTaskOutcomeStats :one
+SELECT COUNT(*) AS finished,
+  COUNT(*) FILTER (WHERE status IN ('completed', 'unchanged')) AS succeeded,
+  (percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - started_at)))::float8 AS p50_seconds,
+  (percentile_cont(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM completed_at - started_at)))::float8 AS p95_seconds
+FROM tasks
+WHERE status IN ('completed', 'unchanged', 'failed')
+AND completed_at >= @window_start::timestamptz
+AND completed_at < @window_end::timestamptz;
+
+-- name: GetTaskBacklog :one
+SELECT COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+  COUNT(*) FILTER (WHERE status = 'processing') AS processing,
+  (MIN(created_at) FILTER (WHERE status = 'pending'))::timestamptz AS oldest_pending_at
+FROM tasks
+WHERE status IN ('pending', 'processing');
+
+-- name: CountStaleObjects :one
+SELECT COUNT(*)
+FROM objects
+WHERE last_synced_at < $1 OR last_synced_at IS NULL;
+
+-- name: GetLatestScanLog :one
+SELECT * FROM object_scan_logs
+ORDER BY created_at DESC
+LIMIT 1;
@@ -0,0 +1,29 @@
+-- name: ListTagAliases :many
+SELECT *
+FROM tag_aliases
+ORDER BY alias;
+
+-- name: UpsertTagAlias :one
+INSERT INTO tag_aliases (alias, tag)
+VALUES ($1, $2)
+ON CONFLICT (alias) DO UPDATE SET tag = EXCLUDED.tag
+RETURNING *;
+
+-- name: DeleteTagAlias :execrows
+DELETE FROM tag_aliases
+WHERE alias = $1;
+
+-- name: ListBlockedTags :many
+SELECT *
+FROM tag_blocklist
+ORDER BY tag;
+
+-- name: CreateBlockedTag :one
+INSERT INTO tag_blocklist (tag)
+VALUES ($1)
+ON CONFLICT (tag) DO UPDATE SET tag = EXCLUDED.tag
+RETURNING *;
+
+-- name: DeleteBlockedTag :execrows
+DELETE FROM tag_blocklist
+WHERE tag = $1;
@@ -17,5 +17,12 @@ UPDATE tasks
 SET status = $1, 
   output = $2, 
   error = $3, 
-  completed_at = $4 
-WHERE id = $5;
\ No newline at end of file
+  completed_at = $4, 
+  mapping_version = $5 
+WHERE id = $6;
+-- name: RequeueTask :exec
+UPDATE tasks
+SET status = 'pending',
+  started_at = NULL
+WHERE id = $1
+  AND status = 'processing';
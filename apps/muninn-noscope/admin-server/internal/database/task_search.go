@@ -0,0 +1,237 @@
+package database
+
+// Hand-written: task listing filters and pagination are assembled dynamically,
+// see pagination.go.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// TaskSortFields maps the sort names accepted by the API to SQL expressions
+var TaskSortFields = map[string]sortColumn{
+	"created_at":   {Expr: "t.created_at", Type: "timestamptz"},
+	"started_at":   {Expr: "t.started_at", Type: "timestamptz"},
+	"completed_at": {Expr: "t.completed_at", Type: "timestamptz"},
+	"duration":     {Expr: taskDurationExpr, Type: "float8"},
+}
+
+const DefaultTaskSort = "created_at"
+
+// TaskStatuses lists every value allowed by tasks_status_check
+var TaskStatuses = []string{"pending", "processing", "completed", "failed", "unchanged"}
+
+const taskDurationExpr = "EXTRACT(EPOCH FROM t.completed_at - t.started_at)::float8"
+
+// TimeRange is an optional half-open [After, Before) window
+type TimeRange struct {
+	After  *time.Time
+	Before *time.Time
+}
+
+// JSONCondition compares a value at a dotted path inside a task's input or output
+type JSONCondition struct {
+	Column string
+	Path   []string
+	Op     string
+	Value  string
+	// Quoted values are always strings; unquoted ones keep their JSON type
+	Quoted bool
+}
+
+var jsonConditionPattern = regexp.MustCompile(`^(input|output)((?:\.[A-Za-z0-9_]+)+)\s*(?:(!=|=|~)\s*(.*))?$`)
+
+// ParseJSONCondition parses "output.organisation = 'X'", "input.email ~ gmail" or "output.linkedin".
+// = and != match the JSON value exactly, ~ is a case-insensitive substring match, and a bare
+// path only requires the key to exist. Quote a value ('42', 'true') to match it as a string.
+func ParseJSONCondition(s string) (JSONCondition, error) {
+	m := jsonConditionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return JSONCondition{}, fmt.Errorf("invalid json condition %q: expected input|output.path [=|!=|~ value]", s)
+	}
+	cond := JSONCondition{
+		Column: m[1],
+		Path:   strings.Split(strings.TrimPrefix(m[2], "."), "."),
+		Op:     m[3],
+		Value:  strings.TrimSpace(m[4]),
+	}
+	if len(cond.Value) >= 2 && cond.Value[0] == '\'' && cond.Value[len(cond.Value)-1] == '\'' {
+		cond.Value = cond.Value[1 : len(cond.Value)-1]
+		cond.Quoted = true
+	}
+	return cond, nil
+}
+
+// containment builds the nested JSON document matched with @> so the jsonb_path_ops
+// index applies. Unquoted values that parse as JSON scalars (numbers, booleans, null) keep
+// their type.
+func (c JSONCondition) containment() (string, error) {
+	var value interface{} = c.Value
+	var typed interface{}
+	if !c.Quoted && json.Unmarshal([]byte(c.Value), &typed) == nil {
+		if _, isObject := typed.(map[string]interface{}); !isObject {
+			value = typed
+		}
+	}
+	for i := len(c.Path) - 1; i >= 0; i-- {
+		value = map[string]interface{}{c.Path[i]: value}
+	}
+	doc, err := json.Marshal(value)
+	return string(doc), err
+}
+
+// TaskFilter narrows SearchTasks; the zero value lists every task
+type TaskFilter struct {
+	ObjectID    *uuid.UUID
+	Statuses    []string
+	Created     TimeRange
+	Started     TimeRange
+	Completed   TimeRange
+	ErrorSearch string
+	JSON        []JSONCondition
+	MinDuration *time.Duration
+	MaxDuration *time.Duration
+	SortBy      string
+	SortAsc     bool
+}
+
+const taskSearchFrom = `
+FROM tasks t`
+
+// likeEscaper escapes LIKE wildcards so ~ matches the value as a literal substring
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+func (f TaskFilter) where() ([]string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	timeRange := func(column string, r TimeRange) {
+		if r.After != nil {
+			conds = append(conds, column+" >= "+arg(*r.After))
+		}
+		if r.Before != nil {
+			conds = append(conds, column+" < "+arg(*r.Before))
+		}
+	}
+
+	if f.ObjectID != nil {
+		conds = append(conds, "t.object_id = "+arg(f.ObjectID))
+	}
+	if len(f.Statuses) == 1 {
+		conds = append(conds, "t.status = "+arg(f.Statuses[0]))
+	} else if len(f.Statuses) > 1 {
+		conds = append(conds, "t.status = ANY("+arg(pq.Array(f.Statuses))+"::text[])")
+	}
+	timeRange("t.created_at", f.Created)
+	timeRange("t.started_at", f.Started)
+	timeRange("t.completed_at", f.Completed)
+	if f.ErrorSearch != "" {
+		conds = append(conds, "to_tsvector('simple', COALESCE(t.error, '')) @@ websearch_to_tsquery('simple', "+arg(f.ErrorSearch)+")")
+	}
+	for _, c := range f.JSON {
+		column := "t." + c.Column
+		switch c.Op {
+		case "":
+			conds = append(conds, column+" #> "+arg(pq.Array(c.Path))+"::text[] IS NOT NULL")
+		case "~":
+			conds = append(conds, column+" #>> "+arg(pq.Array(c.Path))+"::text[] ILIKE '%' || "+arg(escapeLike(c.Value))+` || '%' ESCAPE '\'`)
+		default:
+			// containment() only fails on unmarshalable values, which strings never are
+			doc, _ := c.containment()
+			cond := column + " @> " + arg(doc) + "::jsonb"
+			if c.Op == "!=" {
+				cond = "NOT COALESCE(" + cond + ", false)"
+			}
+			conds = append(conds, cond)
+		}
+	}
+	if f.MinDuration != nil {
+		conds = append(conds, taskDurationExpr+" >= "+arg(f.MinDuration.Seconds()))
+	}
+	if f.MaxDuration != nil {
+		conds = append(conds, taskDurationExpr+" <= "+arg(f.MaxDuration.Seconds()))
+	}
+	return conds, args
+}
+
+func (f TaskFilter) pageQuery(p Page) pageQuery {
+	sortBy := f.SortBy
+	if _, ok := TaskSortFields[sortBy]; !ok {
+		sortBy = DefaultTaskSort
+	}
+	conds, args := f.where()
+	return pageQuery{
+		Columns: "t.id, t.object_id, t.status, t.input, t.output, t.error, t.created_at, t.started_at, t.completed_at, t.mapping_version, t.source",
+		From:    taskSearchFrom,
+		Conds:   conds,
+		Args:    args,
+		Sort:    TaskSortFields[sortBy],
+		SortBy:  sortBy,
+		Asc:     f.SortAsc,
+		IDExpr:  "t.id",
+		Page:    p,
+	}
+}
+
+func (q *Queries) SearchTasks(ctx context.Context, f TaskFilter, p Page) ([]Task, PageCursors, error) {
+	page := f.pageQuery(p)
+	query, args := page.build()
+
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, PageCursors{}, err
+	}
+	defer rows.Close()
+	var items []Task
+	var keys []pageKey
+	for rows.Next() {
+		var i Task
+		var key string
+		if err := rows.Scan(
+			&i.ID,
+			&i.ObjectID,
+			&i.Status,
+			&i.Input,
+			&i.Output,
+			&i.Error,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.CompletedAt,
+			&i.MappingVersion,
+			&i.Source,
+			&key,
+		); err != nil {
+			return nil, PageCursors{}, err
+		}
+		items = append(items, i)
+		keys = append(keys, pageKey{Key: key, ID: *i.ID})
+	}
+	if err := rows.Close(); err != nil {
+		return nil, PageCursors{}, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, PageCursors{}, err
+	}
+
+	items, cursors := finishPage(page, items, keys)
+	return items, cursors, nil
+}
+
+func (q *Queries) CountSearchTasks(ctx context.Context, f TaskFilter, mode CountMode) (*int64, error) {
+	conds, args := f.where()
+	return q.count(ctx, mode, taskSearchFrom, conds, args)
+}
@@ -0,0 +1,124 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONCondition(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    JSONCondition
+		wantDoc string
+		wantErr bool
+	}{
+		{
+			in:      "output.organisation = 'Acme'",
+			want:    JSONCondition{Column: "output", Path: []string{"organisation"}, Op: "=", Value: "Acme", Quoted: true},
+			wantDoc: `{"organisation":"Acme"}`,
+		},
+		{
+			in:      "output.organisation = Acme",
+			want:    JSONCondition{Column: "output", Path: []string{"organisation"}, Op: "=", Value: "Acme"},
+			wantDoc: `{"organisation":"Acme"}`,
+		},
+		{
+			in:      "output.meta.count = 42",
+			want:    JSONCondition{Column: "output", Path: []string{"meta", "count"}, Op: "=", Value: "42"},
+			wantDoc: `{"meta":{"count":42}}`,
+		},
+		{
+			in:      "output.meta.count = '42'",
+			want:    JSONCondition{Column: "output", Path: []string{"meta", "count"}, Op: "=", Value: "42", Quoted: true},
+			wantDoc: `{"meta":{"count":"42"}}`,
+		},
+		{
+			in:      "input.active != true",
+			want:    JSONCondition{Column: "input", Path: []string{"active"}, Op: "!=", Value: "true"},
+			wantDoc: `{"active":true}`,
+		},
+		{
+			in:      "input.active = 'true'",
+			want:    JSONCondition{Column: "input", Path: []string{"active"}, Op: "=", Value: "true", Quoted: true},
+			wantDoc: `{"active":"true"}`,
+		},
+		{
+			in:      "output.linkedin = null",
+			want:    JSONCondition{Column: "output", Path: []string{"linkedin"}, Op: "=", Value: "null"},
+			wantDoc: `{"linkedin":null}`,
+		},
+		{
+			in:      "output.linkedin = 'null'",
+			want:    JSONCondition{Column: "output", Path: []string{"linkedin"}, Op: "=", Value: "null", Quoted: true},
+			wantDoc: `{"linkedin":"null"}`,
+		},
+		{
+			in:      `output.raw = {"a":1}`,
+			want:    JSONCondition{Column: "output", Path: []string{"raw"}, Op: "=", Value: `{"a":1}`},
+			wantDoc: `{"raw":"{\"a\":1}"}`,
+		},
+		{
+			in:   "input.email ~ gmail",
+			want: JSONCondition{Column: "input", Path: []string{"email"}, Op: "~", Value: "gmail"},
+		},
+		{
+			in:   "output.linkedin",
+			want: JSONCondition{Column: "output", Path: []string{"linkedin"}},
+		},
+		{in: "metadata.x = 1", wantErr: true},
+		{in: "output = 1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseJSONCondition(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseJSONCondition(%q) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseJSONCondition(%q): %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParseJSONCondition(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if tt.wantDoc == "" {
+				return
+			}
+			doc, err := got.containment()
+			if err != nil {
+				t.Fatalf("containment(): %v", err)
+			}
+			if doc != tt.wantDoc {
+				t.Errorf("containment() = %s, want %s", doc, tt.wantDoc)
+			}
+		})
+	}
+}
+
+func TestTaskFilterLikeEscaping(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "gmail", want: "gmail"},
+		{value: "100%", want: `100\%`},
+		{value: "first_name", want: `first\_name`},
+		{value: `C:\tmp`, want: `C:\\tmp`},
+		{value: `%_\`, want: `\%\_\\`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			f := TaskFilter{JSON: []JSONCondition{{Column: "input", Path: []string{"email"}, Op: "~", Value: tt.value}}}
+			conds, args := f.where()
+			wantCond := `t.input #>> $1::text[] ILIKE '%' || $2 || '%' ESCAPE '\'`
+			if len(conds) != 1 || conds[0] != wantCond {
+				t.Fatalf("where() conds = %q, want [%q]", conds, wantCond)
+			}
+			if len(args) != 2 || args[1] != tt.want {
+				t.Errorf("where() pattern arg = %v, want %q", args[1:], tt.want)
+			}
+		})
+	}
+}
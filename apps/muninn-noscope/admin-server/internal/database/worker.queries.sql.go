@@ -14,6 +14,19 @@ import (
 	"github.com/sqlc-dev/pqtype"
 )
 
+const requeueTask = `-- name: RequeueTask :exec
+UPDATE tasks
+SET status = 'pending',
+  started_at = NULL
+WHERE id = $1
+  AND status = 'processing'
+`
+
+func (q *Queries) RequeueTask(ctx context.Context, id *uuid.UUID) error {
+	_, err := q.exec(ctx, q.requeueTaskStmt, requeueTask, id)
+	return err
+}
+
 const updateTaskProcessing = `-- name: UpdateTaskProcessing :one
 UPDATE tasks 
 SET status = 'processing', 
@@ -47,16 +60,18 @@ UPDATE tasks
 SET status = $1, 
   output = $2, 
   error = $3, 
-  completed_at = $4 
-WHERE id = $5
+  completed_at = $4, 
+  mapping_version = $5 
+WHERE id = $6
 `
 
 type UpdateTaskStatusParams struct {
-	Status      string                `json:"status"`
-	Output      pqtype.NullRawMessage `json:"output"`
-	Error       sql.NullString        `json:"error"`
-	CompletedAt sql.NullTime          `json:"completed_at"`
-	ID          *uuid.UUID            `json:"id"`
+	Status         string                `json:"status"`
+	Output         pqtype.NullRawMessage `json:"output"`
+	Error          sql.NullString        `json:"error"`
+	CompletedAt    sql.NullTime          `json:"completed_at"`
+	MappingVersion sql.NullString        `json:"mapping_version"`
+	ID             *uuid.UUID            `json:"id"`
 }
 
 func (q *Queries) UpdateTaskStatus(ctx context.Context, arg UpdateTaskStatusParams) error {
@@ -65,6 +80,7 @@ func (q *Queries) UpdateTaskStatus(ctx context.Context, arg UpdateTaskStatusPara
 		arg.Output,
 		arg.Error,
 		arg.CompletedAt,
+		arg.MappingVersion,
 		arg.ID,
 	)
 	return err
@@ -0,0 +1,56 @@
+// Package logging configures the structured JSON logger shared by the API, the
+// worker and the scheduler. Correlation fields (request_id, task_id, object_id,
+// worker_id, instance_id) travel in the context and are added to every line
+// logged with it.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+type fieldsKey struct{}
+
+// With returns a copy of ctx whose log lines also carry the given key-value pairs
+func With(ctx context.Context, args ...any) context.Context {
+	fields := fieldsFrom(ctx)
+	merged := make([]any, 0, len(fields)+len(args))
+	merged = append(merged, fields...)
+	merged = append(merged, args...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+func fieldsFrom(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return fields
+}
+
+// contextHandler adds the context's correlation fields to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if fields := fieldsFrom(ctx); len(fields) > 0 {
+		r.Add(fields...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
+
+// New returns a JSON logger writing to w at level
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})
+	return slog.New(contextHandler{handler})
+}
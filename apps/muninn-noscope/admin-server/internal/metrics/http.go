@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware records request latency labelled with the matched chi route pattern,
+// so /objects/{id} is one series rather than one per object
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		HTTPRequestDuration.WithLabelValues(r.Method, route, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+	})
+}
+
+type upstreamKey struct{}
+
+// WithUpstream names the upstream API for requests built from ctx
+func WithUpstream(ctx context.Context, api string) context.Context {
+	return context.WithValue(ctx, upstreamKey{}, api)
+}
+
+type upstreamTransport struct {
+	next http.RoundTripper
+}
+
+// InstrumentTransport wraps next so every call is counted and timed under the API
+// name set with WithUpstream
+func InstrumentTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &upstreamTransport{next: next}
+}
+
+func (t *upstreamTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	api, _ := req.Context().Value(upstreamKey{}).(string)
+	if api == "" {
+		api = "unknown"
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	UpstreamRequests.WithLabelValues(api, code).Inc()
+	UpstreamDuration.WithLabelValues(api, code).Observe(time.Since(start).Seconds())
+	return resp, err
+}
@@ -0,0 +1,131 @@
+// Package metrics holds the Prometheus collectors exposed on GET /metrics
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "noscope"
+
+// Registry is a dedicated registry so only this service's collectors are exposed
+var Registry = prometheus.NewRegistry()
+
+var (
+	TasksProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_processed_total",
+		Help:      "Tasks picked up by a worker.",
+	}, []string{"worker_id"})
+
+	TasksSucceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_succeeded_total",
+		Help:      "Tasks that finished with status completed.",
+	}, []string{"worker_id"})
+
+	TasksUnchanged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_unchanged_total",
+		Help:      "Tasks skipped because the enrichment result was unchanged.",
+	}, []string{"worker_id"})
+
+	TasksFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_failed_total",
+		Help:      "Tasks that finished with status failed, by the step that failed.",
+	}, []string{"worker_id", "error_class"})
+
+	TasksRequeued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_requeued_total",
+		Help:      "Tasks put back to pending because a hard stop or drain deadline cancelled them.",
+	}, []string{"worker_id"})
+
+	TasksInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tasks_in_flight",
+		Help:      "Tasks a worker has claimed and not yet finished.",
+	}, []string{"worker_id"})
+
+	WorkerStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "worker_status",
+		Help:      "1 for the worker's current status (running, paused, draining or stopped), 0 for the others.",
+	}, []string{"worker_id", "status"})
+
+	WorkerDrainDeadline = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "worker_drain_deadline_seconds",
+		Help:      "Unix time at which a draining worker cancels its remaining tasks; 0 when not draining.",
+	}, []string{"worker_id"})
+
+	TaskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "task_processing_duration_seconds",
+		Help:      "Time from a worker picking up a task to recording its outcome.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120},
+	}, []string{"worker_id"})
+
+	UpstreamRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upstream_requests_total",
+		Help:      "Calls to upstream APIs by status code; code is \"error\" when no response arrived.",
+	}, []string{"api", "code"})
+
+	UpstreamDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "upstream_request_duration_seconds",
+		Help:      "Latency of calls to upstream APIs.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"api", "code"})
+
+	SchedulerRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduler_runs_total",
+		Help:      "Scheduled task runs by outcome.",
+	}, []string{"task", "outcome"})
+
+	SchedulerRunDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "scheduler_run_duration_seconds",
+		Help:      "Duration of scheduled task runs.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 15, 30, 60, 150},
+	}, []string{"task", "outcome"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of admin API requests by chi route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		TasksProcessed,
+		TasksSucceeded,
+		TasksUnchanged,
+		TasksFailed,
+		TasksRequeued,
+		TasksInFlight,
+		WorkerStatus,
+		WorkerDrainDeadline,
+		TaskDuration,
+		UpstreamRequests,
+		UpstreamDuration,
+		SchedulerRuns,
+		SchedulerRunDuration,
+		HTTPRequestDuration,
+	)
+}
+
+// Handler serves the registry, negotiating OpenMetrics when the scraper asks for it
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"admin-server/internal/database"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queueScrapeTimeout bounds the count query run on every scrape
+const queueScrapeTimeout = 5 * time.Second
+
+var queueDepthDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "queue_depth"),
+	"Tasks currently in the database by status.",
+	[]string{"status"}, nil,
+)
+
+// queueCollector reads task counts from the database at scrape time, so the gauge
+// survives restarts and agrees with GET /stats
+type queueCollector struct {
+	queries *database.Queries
+	logger  *slog.Logger
+}
+
+// RegisterQueueCollector exposes noscope_queue_depth backed by q
+func RegisterQueueCollector(q *database.Queries, l *slog.Logger) {
+	Registry.MustRegister(&queueCollector{queries: q, logger: l})
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueScrapeTimeout)
+	defer cancel()
+
+	counts, err := c.queries.CountTasksByStatus(ctx)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "collect queue depth failed", "error", err)
+		ch <- prometheus.NewInvalidMetric(queueDepthDesc, err)
+		return
+	}
+
+	depth := make(map[string]int64, len(database.TaskStatuses))
+	for _, status := range database.TaskStatuses {
+		depth[status] = 0
+	}
+	for _, row := range counts {
+		depth[row.Status] = row.Count
+	}
+	for status, n := range depth {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(n), status)
+	}
+}
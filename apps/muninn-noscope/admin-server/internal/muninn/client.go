@@ -0,0 +1,92 @@
+// Package muninn sends authenticated requests to the Muninn API. One Client is
+// shared by every Muninn caller so they reuse a single cached access token.
+package muninn
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"admin-server/internal/metrics"
+	"admin-server/internal/secrets"
+
+	"golang.org/x/time/rate"
+)
+
+type Client struct {
+	http    *http.Client
+	tokens  *tokenSource
+	limiter *rate.Limiter
+	logger  *slog.Logger
+}
+
+// NewClient authenticates with MUNINN_CLIENT_ID and MUNINN_CLIENT_SECRET against
+// MUNINN_AUTH_URL when all three are set, and with the static MUNINN_JWT otherwise
+func NewClient(store *secrets.Store, logger *slog.Logger) *Client {
+	httpClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: metrics.InstrumentTransport(nil),
+	}
+	return &Client{
+		http:    httpClient,
+		tokens:  newTokenSource(store, httpClient, logger),
+		limiter: rate.NewLimiter(rate.Inf, 1),
+		logger:  logger,
+	}
+}
+
+// Missing returns the secrets that must be set before Muninn can be called
+func (c *Client) Missing() []string {
+	return c.tokens.missing()
+}
+
+// SetRateLimit caps requests to Muninn across every caller; token requests are not counted
+func (c *Client) SetRateLimit(limit rate.Limit) {
+	c.limiter.SetLimit(limit)
+}
+
+// Do sends req with a bearer token. On 401 it obtains a new token and retries
+// once, provided the body can be replayed; requests built from a bytes.Reader can.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || req.GetBody == nil && req.Body != nil {
+		return resp, err
+	}
+
+	// Keep the 401 readable for the caller in case no newer token is available
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	next, err := c.tokens.Renew(ctx, token)
+	if err != nil || next == token {
+		if err != nil {
+			c.logger.WarnContext(ctx, "renew muninn token failed", "error", err)
+		}
+		return resp, nil
+	}
+
+	retry := req.Clone(ctx)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	retry.Header.Set("Authorization", "Bearer "+next)
+	c.logger.InfoContext(ctx, "retrying muninn request with a new token", "url", req.URL.String())
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.http.Do(retry)
+}
@@ -0,0 +1,198 @@
+package muninn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"admin-server/internal/metrics"
+	"admin-server/internal/secrets"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// refreshAfter is the share of a token's lifetime after which a new one is fetched
+	refreshAfter = 0.8
+	// retryDelay spaces out attempts while the auth endpoint is failing and the current token is still valid
+	retryDelay = 10 * time.Second
+)
+
+// tokenSource caches a Muninn access token. With service credentials it fetches
+// tokens through the OAuth2 client credentials grant; without them it hands out
+// MUNINN_JWT, which can still be rotated through the secrets store.
+type tokenSource struct {
+	secrets *secrets.Store
+	client  *http.Client
+	logger  *slog.Logger
+
+	// mu is held while a token is fetched so concurrent callers share one request
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time // zero when the token's lifetime is unknown
+	refreshAt time.Time
+	// credentials is the client ID and secret version the token was fetched with
+	credentials string
+	// reloadedFor is the last static token that secrets were reloaded for, so
+	// callers rejected with the same token share one reload
+	reloadedFor string
+}
+
+func newTokenSource(store *secrets.Store, client *http.Client, logger *slog.Logger) *tokenSource {
+	return &tokenSource{secrets: store, client: client, logger: logger}
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// serviceCredentials reports whether every secret needed to fetch tokens is set
+func (s *tokenSource) serviceCredentials() bool {
+	return len(s.secrets.Missing(secrets.MuninnAuthURL, secrets.MuninnClientID, secrets.MuninnClientSecret)) == 0
+}
+
+func (s *tokenSource) missing() []string {
+	if s.serviceCredentials() {
+		return nil
+	}
+	return s.secrets.Missing(secrets.MuninnJWT)
+}
+
+// Token returns a token that is not close to expiry, fetching a new one when needed
+func (s *tokenSource) Token(ctx context.Context) (string, error) {
+	if !s.serviceCredentials() {
+		return s.staticToken()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	fresh := s.token != "" && s.credentials == s.credentialsVersion() &&
+		(s.refreshAt.IsZero() || now.Before(s.refreshAt))
+	if fresh {
+		return s.token, nil
+	}
+	if err := s.fetch(ctx); err != nil {
+		// A token that has not expired yet is still better than failing the call
+		if s.token != "" && (s.expiresAt.IsZero() || now.Before(s.expiresAt)) {
+			s.logger.WarnContext(ctx, "refresh muninn token failed, using current token", "error", err, "expires_at", s.expiresAt)
+			s.refreshAt = now.Add(retryDelay)
+			return s.token, nil
+		}
+		return "", err
+	}
+	return s.token, nil
+}
+
+// Renew replaces rejected, which Muninn answered with 401, and returns the new
+// token. It returns rejected itself when no other token is available.
+func (s *tokenSource) Renew(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.serviceCredentials() {
+		// MUNINN_JWT may have been rotated since the last reload
+		if rejected != s.reloadedFor {
+			s.reloadedFor = rejected
+			if err := s.secrets.Reload(ctx); err != nil {
+				s.logger.WarnContext(ctx, "reload secrets failed", "error", err)
+			}
+		}
+		return s.staticToken()
+	}
+
+	// Another caller may already have replaced the token
+	if s.token != rejected && s.token != "" {
+		return s.token, nil
+	}
+	if err := s.fetch(ctx); err != nil {
+		return rejected, err
+	}
+	return s.token, nil
+}
+
+func (s *tokenSource) staticToken() (string, error) {
+	token := s.secrets.Get(secrets.MuninnJWT)
+	if token == "" {
+		return "", fmt.Errorf("no muninn credentials: set %s, %s and %s, or %s",
+			secrets.MuninnAuthURL, secrets.MuninnClientID, secrets.MuninnClientSecret, secrets.MuninnJWT)
+	}
+	return token, nil
+}
+
+// credentialsVersion changes when the client ID or secret is rotated, so tokens
+// fetched with the old credentials are replaced
+func (s *tokenSource) credentialsVersion() string {
+	return secrets.Fingerprint(s.secrets.Get(secrets.MuninnClientID) + "\x00" + s.secrets.Get(secrets.MuninnClientSecret))
+}
+
+// fetch runs the client credentials grant. Callers hold s.mu.
+func (s *tokenSource) fetch(ctx context.Context) error {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {s.secrets.Get(secrets.MuninnClientID)},
+		"client_secret": {s.secrets.Get(secrets.MuninnClientSecret)},
+	}
+	req, err := http.NewRequestWithContext(metrics.WithUpstream(ctx, "muninn_auth"), "POST", s.secrets.Get(secrets.MuninnAuthURL), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read token response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("muninn auth returned status code %d: %s", resp.StatusCode, string(body))
+	}
+	var result tokenResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("parse token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return fmt.Errorf("muninn auth response has no access_token")
+	}
+
+	now := time.Now()
+	var lifetime time.Duration
+	if result.ExpiresIn > 0 {
+		lifetime = time.Duration(result.ExpiresIn) * time.Second
+	} else if exp := tokenExpiry(result.AccessToken); !exp.IsZero() {
+		lifetime = exp.Sub(now)
+	}
+
+	s.token = result.AccessToken
+	s.credentials = s.credentialsVersion()
+	s.expiresAt, s.refreshAt = time.Time{}, time.Time{}
+	if lifetime > 0 {
+		s.expiresAt = now.Add(lifetime)
+		s.refreshAt = now.Add(time.Duration(float64(lifetime) * refreshAfter))
+	}
+	s.logger.InfoContext(ctx, "fetched muninn token", "expires_at", s.expiresAt)
+	return nil
+}
+
+// tokenExpiry reads the exp claim without verifying the token; Muninn verifies it.
+// It returns the zero time when the token is not a JWT or has no exp.
+func tokenExpiry(token string) time.Time {
+	claims := jwt.RegisteredClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+	return claims.ExpiresAt.Time
+}
@@ -0,0 +1,99 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"admin-server/internal/database"
+)
+
+// DBProvider keeps secrets in the secrets table, sealed with AES-256-GCM. The
+// secret's name is bound in as additional data, so a ciphertext copied onto
+// another row fails to open.
+type DBProvider struct {
+	queries *database.Queries
+	aead    cipher.AEAD
+}
+
+// NewDBProvider seals values with key, which must be 32 bytes
+func NewDBProvider(queries *database.Queries, key []byte) (*DBProvider, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("secrets encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &DBProvider{queries: queries, aead: aead}, nil
+}
+
+// ParseKey decodes a 32-byte encryption key given as base64 or hex
+func ParseKey(s string) ([]byte, error) {
+	if key, err := base64.StdEncoding.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := hex.DecodeString(s); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, fmt.Errorf("expected 32 bytes encoded as base64 or hex")
+}
+
+func (p *DBProvider) Name() string {
+	return "database"
+}
+
+func (p *DBProvider) Load(ctx context.Context) (map[string]Secret, error) {
+	rows, err := p.queries.ListSecrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list secrets: %w", err)
+	}
+	loaded := make(map[string]Secret, len(rows))
+	for _, row := range rows {
+		value, err := p.aead.Open(nil, row.Nonce, row.Ciphertext, []byte(row.Name))
+		if err != nil {
+			return nil, fmt.Errorf("decrypt secret %s: wrong encryption key or corrupt row", row.Name)
+		}
+		loaded[row.Name] = Secret{
+			Value:     string(value),
+			Source:    p.Name(),
+			Version:   strconv.Itoa(int(row.Version)),
+			UpdatedAt: row.UpdatedAt,
+		}
+	}
+	return loaded, nil
+}
+
+// Set encrypts value and stores it under name, bumping its version
+func (p *DBProvider) Set(ctx context.Context, name, value, updatedBy string) error {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generate nonce: %w", err)
+	}
+	_, err := p.queries.UpsertSecret(ctx, database.UpsertSecretParams{
+		Name:       name,
+		Ciphertext: p.aead.Seal(nil, nonce, []byte(value), []byte(name)),
+		Nonce:      nonce,
+		UpdatedBy:  sql.NullString{String: updatedBy, Valid: updatedBy != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("store secret %s: %w", name, err)
+	}
+	return nil
+}
+
+// Delete removes name, reporting whether it existed
+func (p *DBProvider) Delete(ctx context.Context, name string) (bool, error) {
+	n, err := p.queries.DeleteSecret(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("delete secret %s: %w", name, err)
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,30 @@
+package secrets
+
+import (
+	"context"
+	"os"
+)
+
+// EnvProvider reads secrets from the process environment. The environment is
+// fixed once the process starts, so these values only change on restart.
+type EnvProvider struct {
+	names []string
+}
+
+func NewEnvProvider(names ...string) *EnvProvider {
+	return &EnvProvider{names: names}
+}
+
+func (p *EnvProvider) Name() string {
+	return "env"
+}
+
+func (p *EnvProvider) Load(ctx context.Context) (map[string]Secret, error) {
+	loaded := make(map[string]Secret, len(p.names))
+	for _, name := range p.names {
+		if v := os.Getenv(name); v != "" {
+			loaded[name] = Secret{Value: v, Source: p.Name(), Version: Fingerprint(v)}
+		}
+	}
+	return loaded, nil
+}
@@ -0,0 +1,60 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileProvider reads mounted secrets: one file per secret, named after it, as
+// Docker and Kubernetes secret volumes lay them out. Files are read again on
+// every load, so replacing a file rotates the secret.
+type FileProvider struct {
+	dir string
+}
+
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+func (p *FileProvider) Name() string {
+	return "file"
+}
+
+func (p *FileProvider) Load(ctx context.Context) (map[string]Secret, error) {
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read secrets directory: %w", err)
+	}
+	loaded := make(map[string]Secret, len(entries))
+	for _, entry := range entries {
+		// Kubernetes keeps the real files in hidden ..data directories behind symlinks
+		name := entry.Name()
+		if !ValidName(name) {
+			continue
+		}
+		path := filepath.Join(p.dir, name)
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read secret %s: %w", name, err)
+		}
+		// Editors and echo leave a trailing newline that is never part of the value
+		value := strings.TrimRight(string(raw), "\r\n")
+		if value == "" {
+			continue
+		}
+		loaded[name] = Secret{
+			Value:     value,
+			Source:    p.Name(),
+			Version:   Fingerprint(value),
+			UpdatedAt: info.ModTime(),
+		}
+	}
+	return loaded, nil
+}
@@ -0,0 +1,89 @@
+// Package secrets resolves credentials and upstream endpoints from pluggable
+// backends. A Store caches the merged values and reloads them in the background,
+// so a rotated secret is picked up by the next call that reads it.
+package secrets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"time"
+)
+
+// Names of the values the worker reads through a Store
+const (
+	NoscopeEnrichURL       = "NOSCOPE_ENRICH_URL"
+	NoscopeKey             = "NOSCOPE_KEY"
+	MuninnJWT              = "MUNINN_JWT"
+	MuninnAuthURL          = "MUNINN_AUTH_URL"
+	MuninnClientID         = "MUNINN_CLIENT_ID"
+	MuninnClientSecret     = "MUNINN_CLIENT_SECRET"
+	MuninnNoscopeObjTypeID = "MUNINN_NOSCOPE_OBJTYPE_ID"
+	MuninnUpsertObjTypeURL = "MUNINN_UPSERT_OBJTYPE_URL"
+	MuninnScanObjectsURL   = "MUNINN_SCAN_OBJECTS_URL"
+	MuninnTagsObjURL       = "MUNINN_TAGS_OBJ_URL"
+	MuninnUntagsObjURL     = "MUNINN_UNTAGS_OBJ_URL"
+	// MuninnObjectURL is the object detail endpoint; the object id is appended
+	MuninnObjectURL = "MUNINN_OBJECT_URL"
+	// MuninnSearchObjectsURL is the object search endpoint the admin UI searches through
+	MuninnSearchObjectsURL = "MUNINN_SEARCH_OBJECTS_URL"
+)
+
+// Known lists every name above; the env backend reads only these
+var Known = []string{
+	NoscopeEnrichURL,
+	NoscopeKey,
+	MuninnJWT,
+	MuninnAuthURL,
+	MuninnClientID,
+	MuninnClientSecret,
+	MuninnNoscopeObjTypeID,
+	MuninnUpsertObjTypeURL,
+	MuninnScanObjectsURL,
+	MuninnTagsObjURL,
+	MuninnUntagsObjURL,
+	MuninnObjectURL,
+	MuninnSearchObjectsURL,
+}
+
+// ErrReadOnly is returned when writing a secret and no backend accepts writes
+var ErrReadOnly = errors.New("no writable secrets backend is configured")
+
+// Secret names look like environment variables so every backend can hold any of them
+var namePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Secret is one resolved value. Version identifies the value without revealing it.
+type Secret struct {
+	Value     string
+	Source    string
+	Version   string
+	UpdatedAt time.Time
+}
+
+// Provider is a backend secrets are loaded from
+type Provider interface {
+	// Name identifies the backend in version listings, e.g. "env"
+	Name() string
+	// Load returns every secret the backend currently holds, keyed by name
+	Load(ctx context.Context) (map[string]Secret, error)
+}
+
+// Writer is implemented by backends that can store values at runtime
+type Writer interface {
+	Set(ctx context.Context, name, value, updatedBy string) error
+	Delete(ctx context.Context, name string) (bool, error)
+}
+
+// ValidName reports whether name can be used as a secret name
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Fingerprint is the version reported for backends that do not number their
+// values: a short digest that changes whenever the value does
+func Fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:6])
+}
@@ -0,0 +1,240 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store merges its providers and caches the result. Earlier providers take
+// precedence: main orders them database, file, env, so a value set at runtime
+// overrides a mounted file, which overrides the environment.
+type Store struct {
+	providers []Provider
+	logger    *slog.Logger
+
+	mu sync.RWMutex
+	// loaded keeps the last successful load of each provider, by index
+	loaded   []map[string]Secret
+	errs     []error
+	merged   map[string]Secret
+	loadedAt time.Time
+}
+
+func NewStore(logger *slog.Logger, providers ...Provider) *Store {
+	return &Store{
+		providers: providers,
+		logger:    logger,
+		loaded:    make([]map[string]Secret, len(providers)),
+		errs:      make([]error, len(providers)),
+		merged:    map[string]Secret{},
+	}
+}
+
+// Get returns the active value of name, or "" when no provider has one
+func (s *Store) Get(name string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.merged[name].Value
+}
+
+// Missing returns the names no provider has a value for
+func (s *Store) Missing(names ...string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var missing []string
+	for _, name := range names {
+		if _, ok := s.merged[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Reload loads every provider. A provider that fails keeps its previous values,
+// so a transient database or filesystem error does not blank out credentials.
+func (s *Store) Reload(ctx context.Context) error {
+	results := make([]map[string]Secret, len(s.providers))
+	errs := make([]error, len(s.providers))
+	for i, p := range s.providers {
+		results[i], errs[i] = p.Load(ctx)
+		if errs[i] != nil {
+			errs[i] = fmt.Errorf("%s: %w", p.Name(), errs[i])
+		}
+	}
+
+	s.mu.Lock()
+	for i := range s.providers {
+		if errs[i] == nil {
+			s.loaded[i] = results[i]
+		}
+	}
+	merged := map[string]Secret{}
+	for i := len(s.loaded) - 1; i >= 0; i-- {
+		for name, secret := range s.loaded[i] {
+			merged[name] = secret
+		}
+	}
+	previous := s.merged
+	first := s.loadedAt.IsZero()
+	s.merged, s.errs, s.loadedAt = merged, errs, time.Now()
+	s.mu.Unlock()
+
+	if !first {
+		s.logChanges(ctx, previous, merged)
+	}
+	return errors.Join(errs...)
+}
+
+// logChanges records rotations by version, never by value
+func (s *Store) logChanges(ctx context.Context, previous, current map[string]Secret) {
+	for name, secret := range current {
+		old, ok := previous[name]
+		if !ok || old.Source != secret.Source || old.Version != secret.Version {
+			s.logger.InfoContext(ctx, "secret changed", "name", name, "source", secret.Source, "version", secret.Version)
+		}
+	}
+	for name := range previous {
+		if _, ok := current[name]; !ok {
+			s.logger.WarnContext(ctx, "secret removed", "name", name)
+		}
+	}
+}
+
+// Watch reloads every interval until ctx is cancelled
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil && ctx.Err() == nil {
+				s.logger.WarnContext(ctx, "reload secrets failed", "error", err)
+			}
+		}
+	}
+}
+
+// writer returns the highest-precedence provider that accepts writes
+func (s *Store) writer() Writer {
+	for _, p := range s.providers {
+		if w, ok := p.(Writer); ok {
+			return w
+		}
+	}
+	return nil
+}
+
+// Writable reports whether Set and Delete have a backend to write to
+func (s *Store) Writable() bool {
+	return s.writer() != nil
+}
+
+// Set stores value in the writable backend and reloads so it takes effect immediately
+func (s *Store) Set(ctx context.Context, name, value, updatedBy string) error {
+	w := s.writer()
+	if w == nil {
+		return ErrReadOnly
+	}
+	if err := w.Set(ctx, name, value, updatedBy); err != nil {
+		return err
+	}
+	s.reloadAfterWrite(ctx)
+	return nil
+}
+
+// Delete removes name from the writable backend, after which a lower-precedence
+// backend's value, if any, becomes active
+func (s *Store) Delete(ctx context.Context, name string) (bool, error) {
+	w := s.writer()
+	if w == nil {
+		return false, ErrReadOnly
+	}
+	deleted, err := w.Delete(ctx, name)
+	if err != nil || !deleted {
+		return deleted, err
+	}
+	s.reloadAfterWrite(ctx)
+	return true, nil
+}
+
+// reloadAfterWrite applies a write at once. The write itself succeeded, so a
+// failing backend is only logged; it also shows in Providers.
+func (s *Store) reloadAfterWrite(ctx context.Context) {
+	if err := s.Reload(ctx); err != nil {
+		s.logger.WarnContext(ctx, "reload secrets failed", "error", err)
+	}
+}
+
+// Version describes the active value of a secret without revealing it
+type Version struct {
+	Name      string
+	Source    string
+	Version   string
+	UpdatedAt time.Time
+	// Shadowed lists lower-precedence backends that also hold a value
+	Shadowed []string
+}
+
+// Versions lists the active secrets by name
+func (s *Store) Versions() []Version {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := make([]Version, 0, len(s.merged))
+	for name, secret := range s.merged {
+		v := Version{
+			Name:      name,
+			Source:    secret.Source,
+			Version:   secret.Version,
+			UpdatedAt: secret.UpdatedAt,
+		}
+		active := false
+		for i, p := range s.providers {
+			if _, ok := s.loaded[i][name]; !ok {
+				continue
+			}
+			if active {
+				v.Shadowed = append(v.Shadowed, p.Name())
+			}
+			active = true
+		}
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Name < versions[j].Name
+	})
+	return versions
+}
+
+// ProviderStatus is the outcome of the last load of one backend
+type ProviderStatus struct {
+	Name     string
+	Writable bool
+	Secrets  int
+	Error    string
+}
+
+// Providers reports each backend in precedence order and when the store last reloaded
+func (s *Store) Providers() ([]ProviderStatus, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	statuses := make([]ProviderStatus, len(s.providers))
+	for i, p := range s.providers {
+		_, writable := p.(Writer)
+		statuses[i] = ProviderStatus{
+			Name:     p.Name(),
+			Writable: writable,
+			Secrets:  len(s.loaded[i]),
+		}
+		if s.errs[i] != nil {
+			statuses[i].Error = s.errs[i].Error()
+		}
+	}
+	return statuses, s.loadedAt
+}
@@ -0,0 +1,232 @@
+// Package settings holds the worker knobs that can be changed at runtime. Values
+// start from configuration, rows in the settings table override them, and a
+// Store tells subscribers about changes so the worker applies them without a restart.
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Keys of the runtime settings, as used in the API and the settings table
+const (
+	PollInterval     = "poll_interval"
+	PoolSize         = "pool_size"
+	StaleDays        = "stale_days"
+	ScanInterval     = "scan_interval"
+	DataModels       = "data_models"
+	NoscopeRateLimit = "noscope_rate_limit"
+	MuninnRateLimit  = "muninn_rate_limit"
+	// The desired worker state is stored as settings so every process applies it,
+	// whichever one received the request
+	Processor            = "processor"
+	Scheduler            = "scheduler"
+	PausedScheduledTasks = "paused_scheduled_tasks"
+)
+
+// Desired component states
+const (
+	StateRunning = "running"
+	StatePaused  = "paused"
+	StateStopped = "stopped"
+)
+
+// ProcessorState is the state every task processor should be in
+type ProcessorState struct {
+	// State is running, paused or stopped
+	State string `json:"state"`
+	// DrainUntil lets a stop finish the tasks in flight, cancelling those still
+	// running at that time; without it a stop cancels them at once
+	DrainUntil *time.Time `json:"drain_until,omitempty"`
+}
+
+// Settings is the effective value of every knob
+type Settings struct {
+	// PollInterval is how often the worker looks for pending tasks
+	PollInterval time.Duration
+	// PoolSize caps how many tasks are processed at once
+	PoolSize int
+	// StaleDays is how long after its last sync an object is refreshed
+	StaleDays int
+	// ScanInterval is how often the scan task runs
+	ScanInterval time.Duration
+	// DataModels are the fields requested from Noscope
+	DataModels []string
+	// Rate limits are requests per minute to each upstream; 0 means unlimited
+	NoscopeRateLimit int
+	MuninnRateLimit  int
+	// Processor and Scheduler are the desired component states; PausedScheduledTasks
+	// names the scheduled tasks that should not run
+	Processor            ProcessorState
+	Scheduler            string
+	PausedScheduledTasks []string
+}
+
+// StaleAfter is StaleDays as a duration
+func (s Settings) StaleAfter() time.Duration {
+	return time.Duration(s.StaleDays) * 24 * time.Hour
+}
+
+func (s Settings) clone() Settings {
+	s.DataModels = slices.Clone(s.DataModels)
+	s.PausedScheduledTasks = slices.Clone(s.PausedScheduledTasks)
+	return s
+}
+
+// RateLimit converts a requests-per-minute setting for rate.Limiter
+func RateLimit(perMinute int) rate.Limit {
+	if perMinute <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(float64(perMinute) / 60)
+}
+
+// field describes one setting: how it is shown and how a JSON value is checked and applied
+type field struct {
+	description string
+	get         func(*Settings) interface{}
+	set         func(*Settings, json.RawMessage) error
+}
+
+// Keys lists the settings in display order
+var Keys = []string{PollInterval, PoolSize, StaleDays, ScanInterval, DataModels, NoscopeRateLimit, MuninnRateLimit, Processor, Scheduler, PausedScheduledTasks}
+
+var fields = map[string]field{
+	PollInterval: durationField("How often the worker looks for pending tasks", func(s *Settings) *time.Duration { return &s.PollInterval }, 100*time.Millisecond, time.Hour),
+	PoolSize:     intField("Most tasks processed at once", func(s *Settings) *int { return &s.PoolSize }, 1, 100),
+	StaleDays:    intField("Days after its last sync that an object is refreshed", func(s *Settings) *int { return &s.StaleDays }, 1, 3650),
+	ScanInterval: durationField("How often Muninn is scanned for new and stale objects", func(s *Settings) *time.Duration { return &s.ScanInterval }, 10*time.Second, 24*time.Hour),
+	DataModels: {
+		description: "Fields requested from Noscope",
+		get:         func(s *Settings) interface{} { return s.DataModels },
+		set: func(s *Settings, raw json.RawMessage) error {
+			var models []string
+			if err := json.Unmarshal(raw, &models); err != nil {
+				return fmt.Errorf("expected an array of strings")
+			}
+			if len(models) == 0 {
+				return fmt.Errorf("at least one data model is required")
+			}
+			seen := make(map[string]bool, len(models))
+			for i, m := range models {
+				m = strings.TrimSpace(m)
+				if m == "" || seen[m] {
+					return fmt.Errorf("data models must be unique and non-empty")
+				}
+				seen[m] = true
+				models[i] = m
+			}
+			s.DataModels = models
+			return nil
+		},
+	},
+	NoscopeRateLimit: intField("Requests per minute to Noscope; 0 is unlimited", func(s *Settings) *int { return &s.NoscopeRateLimit }, 0, 100000),
+	MuninnRateLimit:  intField("Requests per minute to Muninn; 0 is unlimited", func(s *Settings) *int { return &s.MuninnRateLimit }, 0, 100000),
+	Processor: {
+		description: "Desired task processor state: running, paused or stopped, with an optional drain_until",
+		get:         func(s *Settings) interface{} { return s.Processor },
+		set: func(s *Settings, raw json.RawMessage) error {
+			var state ProcessorState
+			if err := json.Unmarshal(raw, &state); err != nil {
+				return fmt.Errorf("expected an object such as {\"state\": \"running\"}")
+			}
+			switch state.State {
+			case StateRunning, StatePaused:
+				if state.DrainUntil != nil {
+					return fmt.Errorf("drain_until only applies to a stop")
+				}
+			case StateStopped:
+			default:
+				return fmt.Errorf("invalid state %q: expected running, paused or stopped", state.State)
+			}
+			s.Processor = state
+			return nil
+		},
+	},
+	Scheduler: {
+		description: "Desired scheduler state: running or stopped",
+		get:         func(s *Settings) interface{} { return s.Scheduler },
+		set: func(s *Settings, raw json.RawMessage) error {
+			var state string
+			if err := json.Unmarshal(raw, &state); err != nil || (state != StateRunning && state != StateStopped) {
+				return fmt.Errorf("expected \"running\" or \"stopped\"")
+			}
+			s.Scheduler = state
+			return nil
+		},
+	},
+	PausedScheduledTasks: {
+		description: "Scheduled tasks that should not run",
+		get: func(s *Settings) interface{} {
+			if s.PausedScheduledTasks == nil {
+				return []string{}
+			}
+			return s.PausedScheduledTasks
+		},
+		set: func(s *Settings, raw json.RawMessage) error {
+			var names []string
+			if err := json.Unmarshal(raw, &names); err != nil {
+				return fmt.Errorf("expected an array of strings")
+			}
+			var paused []string
+			for _, name := range names {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					return fmt.Errorf("task names must be non-empty")
+				}
+				if !slices.Contains(paused, name) {
+					paused = append(paused, name)
+				}
+			}
+			slices.Sort(paused)
+			s.PausedScheduledTasks = paused
+			return nil
+		},
+	},
+}
+
+// Durations are written as Go duration strings such as "5s"
+func durationField(description string, ptr func(*Settings) *time.Duration, min, max time.Duration) field {
+	return field{
+		description: description,
+		get:         func(s *Settings) interface{} { return ptr(s).String() },
+		set: func(s *Settings, raw json.RawMessage) error {
+			var v string
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return fmt.Errorf("expected a duration string such as \"30s\"")
+			}
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", v)
+			}
+			if d < min || d > max {
+				return fmt.Errorf("must be between %s and %s", min, max)
+			}
+			*ptr(s) = d
+			return nil
+		},
+	}
+}
+
+func intField(description string, ptr func(*Settings) *int, min, max int) field {
+	return field{
+		description: description,
+		get:         func(s *Settings) interface{} { return *ptr(s) },
+		set: func(s *Settings, raw json.RawMessage) error {
+			var v int
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return fmt.Errorf("expected an integer")
+			}
+			if v < min || v > max {
+				return fmt.Errorf("must be between %d and %d", min, max)
+			}
+			*ptr(s) = v
+			return nil
+		},
+	}
+}
@@ -0,0 +1,331 @@
+package settings
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"admin-server/internal/database"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// ErrUnknown is returned for a key that is not in Keys
+var ErrUnknown = errors.New("unknown setting")
+
+// ValueError is a value the setting does not accept
+type ValueError struct {
+	Key string
+	Err error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("invalid %s: %v", e.Key, e.Err)
+}
+
+// Store holds the effective settings. Changes made through it apply at once;
+// Watch picks up changes made by other instances sharing the database.
+type Store struct {
+	db       *sql.DB
+	queries  *database.Queries
+	defaults Settings
+	// validate checks the combined settings, e.g. data models against the mapping
+	validate func(Settings) error
+	logger   *slog.Logger
+
+	mu          sync.RWMutex
+	current     Settings
+	rows        map[string]database.Setting
+	subscribers []func(Settings)
+}
+
+// NewStore starts from defaults until Load reads the settings table. validate may be nil.
+func NewStore(db *sql.DB, defaults Settings, validate func(Settings) error, logger *slog.Logger) *Store {
+	return &Store{
+		db:       db,
+		queries:  database.New(db),
+		defaults: defaults.clone(),
+		validate: validate,
+		logger:   logger,
+		current:  defaults.clone(),
+		rows:     map[string]database.Setting{},
+	}
+}
+
+// Current returns a copy of the effective settings
+func (s *Store) Current() Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current.clone()
+}
+
+// Subscribe calls fn with the new settings after every change
+func (s *Store) Subscribe(fn func(Settings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+// Load reads the settings table over the defaults. A stored value that is no
+// longer valid is logged and skipped, so the default applies instead.
+func (s *Store) Load(ctx context.Context) error {
+	rows, err := s.queries.ListSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("list settings: %w", err)
+	}
+	next := s.defaults.clone()
+	stored := make(map[string]database.Setting, len(rows))
+	for _, row := range rows {
+		f, ok := fields[row.Key]
+		if !ok {
+			s.logger.WarnContext(ctx, "ignoring unknown setting", "key", row.Key)
+			continue
+		}
+		trial := next.clone()
+		if err := f.set(&trial, row.Value); err != nil {
+			s.logger.WarnContext(ctx, "ignoring invalid setting", "key", row.Key, "error", err)
+			continue
+		}
+		if s.validate != nil {
+			if err := s.validate(trial); err != nil {
+				s.logger.WarnContext(ctx, "ignoring invalid setting", "key", row.Key, "error", err)
+				continue
+			}
+		}
+		next = trial
+		stored[row.Key] = row
+	}
+
+	s.mu.Lock()
+	changed := !reflect.DeepEqual(s.current, next)
+	s.current, s.rows = next, stored
+	s.mu.Unlock()
+
+	if changed {
+		s.notify(ctx, next)
+	}
+	return nil
+}
+
+// notify logs next and passes it to every subscriber
+func (s *Store) notify(ctx context.Context, next Settings) {
+	s.mu.RLock()
+	subscribers := s.subscribers
+	s.mu.RUnlock()
+	s.logger.InfoContext(ctx, "settings changed", "settings", next.clone())
+	for _, fn := range subscribers {
+		fn(next.clone())
+	}
+}
+
+// reloadAfterChange applies committed changes. If the table cannot be read back
+// the changes are applied to this instance alone; the next Watch reload catches
+// up. changed maps each key to its stored value, or to nil when it was reset to
+// its default.
+func (s *Store) reloadAfterChange(ctx context.Context, changed map[string]*database.Setting) {
+	err := s.Load(ctx)
+	if err == nil {
+		return
+	}
+	keys := slices.Sorted(maps.Keys(changed))
+	s.logger.WarnContext(ctx, "reload settings failed, applying change locally", "keys", keys, "error", err)
+
+	s.mu.Lock()
+	next := s.current.clone()
+	rows := maps.Clone(s.rows)
+	for _, key := range keys {
+		f, row := fields[key], changed[key]
+		value := json.RawMessage(nil)
+		if row != nil {
+			value = row.Value
+		} else if value, err = json.Marshal(f.get(&s.defaults)); err != nil {
+			s.mu.Unlock()
+			s.logger.ErrorContext(ctx, "apply setting locally failed", "key", key, "error", err)
+			return
+		}
+		if err := f.set(&next, value); err != nil {
+			s.mu.Unlock()
+			s.logger.ErrorContext(ctx, "apply setting locally failed", "key", key, "error", err)
+			return
+		}
+		if row != nil {
+			rows[key] = *row
+		} else {
+			delete(rows, key)
+		}
+	}
+	updated := !reflect.DeepEqual(s.current, next)
+	s.current, s.rows = next, rows
+	s.mu.Unlock()
+
+	if updated {
+		s.notify(ctx, next)
+	}
+}
+
+// Watch reloads every interval until ctx is cancelled
+func (s *Store) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(ctx); err != nil && ctx.Err() == nil {
+				s.logger.WarnContext(ctx, "reload settings failed", "error", err)
+			}
+		}
+	}
+}
+
+// Set validates value, stores it with a change record naming changedBy, and applies it
+func (s *Store) Set(ctx context.Context, key string, value json.RawMessage, changedBy *uuid.UUID) error {
+	return s.SetMany(ctx, map[string]json.RawMessage{key: value}, changedBy)
+}
+
+// SetMany is Set for several keys at once. The values are validated together and
+// stored in one transaction, so either every one of them applies or none does.
+func (s *Store) SetMany(ctx context.Context, values map[string]json.RawMessage, changedBy *uuid.UUID) error {
+	keys := slices.Sorted(maps.Keys(values))
+	trial := s.Current()
+	for _, key := range keys {
+		f, ok := fields[key]
+		if !ok {
+			return ErrUnknown
+		}
+		if err := f.set(&trial, values[key]); err != nil {
+			return &ValueError{Key: key, Err: err}
+		}
+	}
+	if s.validate != nil {
+		if err := s.validate(trial); err != nil {
+			return &ValueError{Key: strings.Join(keys, ", "), Err: err}
+		}
+	}
+
+	changed := make(map[string]*database.Setting, len(keys))
+	err := s.change(ctx, func(q *database.Queries) error {
+		for _, key := range keys {
+			// Store the normalised form, e.g. "1m0s" rather than "60s"
+			normalised, err := json.Marshal(fields[key].get(&trial))
+			if err != nil {
+				return err
+			}
+			var old pqtype.NullRawMessage
+			previous, err := q.GetSettingForUpdate(ctx, key)
+			if err == nil {
+				old = pqtype.NullRawMessage{RawMessage: previous.Value, Valid: true}
+			} else if err != sql.ErrNoRows {
+				return err
+			}
+			if _, err := q.UpsertSetting(ctx, database.UpsertSettingParams{Key: key, Value: normalised, UpdatedBy: changedBy}); err != nil {
+				return err
+			}
+			if err := recordChange(ctx, q, key, old, pqtype.NullRawMessage{RawMessage: normalised, Valid: true}, changedBy); err != nil {
+				return err
+			}
+			changed[key] = &database.Setting{Key: key, Value: normalised, UpdatedBy: changedBy, UpdatedAt: time.Now()}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.reloadAfterChange(ctx, changed)
+	return nil
+}
+
+// Reset deletes the stored value so the configured default applies again,
+// reporting whether there was one
+func (s *Store) Reset(ctx context.Context, key string, changedBy *uuid.UUID) (bool, error) {
+	if _, ok := fields[key]; !ok {
+		return false, ErrUnknown
+	}
+	err := s.change(ctx, func(q *database.Queries) error {
+		previous, err := q.DeleteSetting(ctx, key)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, q, key, pqtype.NullRawMessage{RawMessage: previous.Value, Valid: true}, pqtype.NullRawMessage{}, changedBy)
+	})
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	s.reloadAfterChange(ctx, map[string]*database.Setting{key: nil})
+	return true, nil
+}
+
+// change runs write in one transaction
+func (s *Store) change(ctx context.Context, write func(*database.Queries) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := write(s.queries.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// recordChange adds a change of key to the settings history
+func recordChange(ctx context.Context, q *database.Queries, key string, oldValue, newValue pqtype.NullRawMessage, changedBy *uuid.UUID) error {
+	if _, err := q.CreateSettingChange(ctx, database.CreateSettingChangeParams{
+		Key:       key,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		ChangedBy: changedBy,
+	}); err != nil {
+		return fmt.Errorf("record setting change: %w", err)
+	}
+	return nil
+}
+
+// Entry describes one setting for listings
+type Entry struct {
+	Key         string
+	Description string
+	Value       interface{}
+	Default     interface{}
+	// Overridden is true when the value comes from the settings table
+	Overridden bool
+	UpdatedBy  *uuid.UUID
+	UpdatedAt  time.Time
+}
+
+// List returns every setting in Keys order
+func (s *Store) List() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]Entry, 0, len(Keys))
+	for _, key := range Keys {
+		f := fields[key]
+		entry := Entry{
+			Key:         key,
+			Description: f.description,
+			Value:       f.get(&s.current),
+			Default:     f.get(&s.defaults),
+		}
+		if row, ok := s.rows[key]; ok {
+			entry.Overridden = true
+			entry.UpdatedBy = row.UpdatedBy
+			entry.UpdatedAt = row.UpdatedAt
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -1,102 +1,289 @@
 package util
 
 import (
+	"compress/gzip"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+// CurrentLogName is the symlink in the log directory that always points at the file being written
+const CurrentLogName = "current.log"
+
+const logTimestampFormat = "2006-01-02T150405"
+
+// FileLoggerOptions controls rotation and retention. Zero values disable the matching limit.
+type FileLoggerOptions struct {
+	Directory   string
+	MaxSize     int64         // rotate before a write would grow the file past this many bytes
+	RotateEvery time.Duration // rotate once the file has been open this long
+	MaxAge      time.Duration // delete rotated files older than this
+	MaxFiles    int           // keep at most this many rotated files
+	Compress    bool          // gzip rotated files
+}
+
+// DefaultFileLoggerOptions rotates daily or at 100MB and keeps 30 compressed files for up to 30 days
+func DefaultFileLoggerOptions(directory string) FileLoggerOptions {
+	return FileLoggerOptions{
+		Directory:   directory,
+		MaxSize:     100 << 20,
+		RotateEvery: 24 * time.Hour,
+		MaxAge:      30 * 24 * time.Hour,
+		MaxFiles:    30,
+		Compress:    true,
+	}
+}
+
 type FileLogger struct {
 	*log.Logger
-	mu            sync.Mutex
-	currentFile   *os.File
-	currentLines  int
-	maxLines      int
-	logDirectory  string
+	opts        FileLoggerOptions
+	mu          sync.Mutex
+	currentFile *os.File
+	currentName string
+	currentSize int64
+	openedAt    time.Time
+	// maintenance serialises compression and pruning, which run off the write path
+	maintenance sync.Mutex
+	wg          sync.WaitGroup
 }
 
-// NewFileLogger creates a new logger that writes to timestamped files
-func NewFileLogger(logDirectory string) (*FileLogger, error) {
+// NewFileLogger creates a logger writing to timestamped files in opts.Directory
+func NewFileLogger(opts FileLoggerOptions) (*FileLogger, error) {
 	// Create logs directory if it doesn't exist
-	if err := os.MkdirAll(logDirectory, 0755); err != nil {
+	if err := os.MkdirAll(opts.Directory, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 
-	logger := &FileLogger{
-		maxLines:     10000,
-		logDirectory: logDirectory,
-	}
+	logger := &FileLogger{opts: opts}
+	// Logger writes through Write so every line is subject to rotation
+	logger.Logger = log.New(logger, "", log.LstdFlags)
 
-	if err := logger.rotateFile(); err != nil {
+	logger.mu.Lock()
+	err := logger.openFile()
+	logger.mu.Unlock()
+	if err != nil {
 		return nil, err
 	}
 
+	logger.wg.Add(1)
+	go func() {
+		defer logger.wg.Done()
+		logger.maintenance.Lock()
+		defer logger.maintenance.Unlock()
+		logger.prune()
+	}()
+
 	return logger, nil
 }
 
-// rotateFile creates a new log file with timestamp
-func (l *FileLogger) rotateFile() error {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	// Close current file if it exists
-	if l.currentFile != nil {
-		l.currentFile.Close()
+// openFile starts a new timestamped file and points the current symlink at it. Callers hold l.mu.
+func (l *FileLogger) openFile() error {
+	now := time.Now()
+	base := now.Format(logTimestampFormat)
+	filename := filepath.Join(l.opts.Directory, base+".log")
+	// Size rotation can happen twice within a second
+	for i := 1; fileExists(filename) || fileExists(filename+".gz"); i++ {
+		filename = filepath.Join(l.opts.Directory, fmt.Sprintf("%s-%d.log", base, i))
 	}
 
-	// Create new file with timestamp
-	timestamp := time.Now().Format("2006-01-02T150405")
-	filename := filepath.Join(l.logDirectory, fmt.Sprintf("%s.log", timestamp))
-	
 	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to create new log file: %w", err)
 	}
 
 	l.currentFile = file
-	l.Logger = log.New(file, "", log.LstdFlags)
-	l.currentLines = 0
+	l.currentName = filepath.Base(filename)
+	l.currentSize = 0
+	l.openedAt = now
+
+	// Replace the symlink atomically so readers never see it missing
+	link := filepath.Join(l.opts.Directory, CurrentLogName)
+	tmp := link + ".tmp"
+	os.Remove(tmp)
+	if err := os.Symlink(filepath.Base(filename), tmp); err == nil {
+		os.Rename(tmp, link)
+	}
+	return nil
+}
 
+// rotate closes the current file and opens a new one. Callers hold l.mu.
+func (l *FileLogger) rotate() error {
+	previous := l.currentFile
+	if err := l.openFile(); err != nil {
+		return err
+	}
+	if previous == nil {
+		return nil
+	}
+	previous.Close()
+
+	l.wg.Add(1)
+	go func(name string) {
+		defer l.wg.Done()
+		l.maintenance.Lock()
+		defer l.maintenance.Unlock()
+		if l.opts.Compress {
+			// The file may already have been pruned by an earlier rotation
+			if err := compressFile(name); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "log rotation: compress %s: %v\n", name, err)
+			}
+		}
+		l.prune()
+	}(previous.Name())
 	return nil
 }
 
-// Write implements io.Writer and handles line counting and rotation
+func (l *FileLogger) shouldRotate(next int) bool {
+	if l.opts.MaxSize > 0 && l.currentSize > 0 && l.currentSize+int64(next) > l.opts.MaxSize {
+		return true
+	}
+	return l.opts.RotateEvery > 0 && time.Since(l.openedAt) >= l.opts.RotateEvery
+}
+
+// Write implements io.Writer and rotates by size and age before writing
 func (l *FileLogger) Write(p []byte) (n int, err error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	// Count newlines in the input
-	newLines := 0
-	for _, b := range p {
-		if b == '\n' {
-			newLines++
-		}
+	if l.currentFile == nil {
+		return 0, os.ErrClosed
 	}
-
-	// Check if we need to rotate
-	if l.currentLines + newLines > l.maxLines {
-		if err := l.rotateFile(); err != nil {
+	if l.shouldRotate(len(p)) {
+		if err := l.rotate(); err != nil {
 			return 0, err
 		}
 	}
 
 	n, err = l.currentFile.Write(p)
-	if err == nil {
-		l.currentLines += newLines
-	}
+	l.currentSize += int64(n)
 	return n, err
 }
 
-// Close closes the current log file
+// Close closes the current log file and waits for pending compression
 func (l *FileLogger) Close() error {
 	l.mu.Lock()
-	defer l.mu.Unlock()
-
+	var err error
 	if l.currentFile != nil {
-		return l.currentFile.Close()
+		err = l.currentFile.Close()
+		l.currentFile = nil
 	}
-	return nil
-}
\ No newline at end of file
+	l.mu.Unlock()
+
+	l.wg.Wait()
+	return err
+}
+
+// Files lists the log files in the directory, newest first, excluding the current symlink
+func (l *FileLogger) Files() ([]os.FileInfo, error) {
+	return ListLogFiles(l.opts.Directory)
+}
+
+// Directory is where the logger writes
+func (l *FileLogger) Directory() string {
+	return l.opts.Directory
+}
+
+// ListLogFiles returns the .log and .log.gz files in dir, newest first
+func ListLogFiles(dir string) ([]os.FileInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []os.FileInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type()&os.ModeSymlink != 0 || entry.IsDir() || !IsLogFileName(name) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().After(files[j].ModTime())
+	})
+	return files, nil
+}
+
+// IsLogFileName reports whether name looks like a file written by FileLogger
+func IsLogFileName(name string) bool {
+	return name != CurrentLogName && (strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz"))
+}
+
+// prune applies MaxAge and MaxFiles to rotated files. Callers hold l.maintenance.
+func (l *FileLogger) prune() {
+	if l.opts.MaxAge <= 0 && l.opts.MaxFiles <= 0 {
+		return
+	}
+	files, err := ListLogFiles(l.opts.Directory)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	current := l.currentName
+	l.mu.Unlock()
+
+	kept := 0
+	for _, f := range files {
+		if f.Name() == current {
+			continue
+		}
+		expired := l.opts.MaxAge > 0 && time.Since(f.ModTime()) > l.opts.MaxAge
+		overLimit := l.opts.MaxFiles > 0 && kept >= l.opts.MaxFiles
+		if expired || overLimit {
+			os.Remove(filepath.Join(l.opts.Directory, f.Name()))
+			continue
+		}
+		kept++
+	}
+}
+
+// compressFile gzips name to name.gz and removes the original
+func compressFile(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		gz.Close()
+		out.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		out.Close()
+		os.Remove(name + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	// Keep the original timestamp so retention ages the file from when it was written
+	os.Chtimes(name+".gz", info.ModTime(), info.ModTime())
+	return os.Remove(name)
+}
+
+func fileExists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"admin-server/internal/database"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// contentHash fingerprints what would be written to Muninn. type_values comes out of
+// Mapping.Apply with sorted keys, and tags are sorted here, so equal content hashes equally.
+func contentHash(typeValues json.RawMessage, tags []string) string {
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	h.Write(typeValues)
+	h.Write([]byte{0})
+	for _, tag := range sorted {
+		h.Write([]byte(tag))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isUnchanged reports whether hash matches the last result applied to the task's object
+func (m *Manager) isUnchanged(ctx context.Context, task *database.UpdateTaskProcessingRow, hash string) bool {
+	obj, err := database.New(m.db).GetObject(ctx, task.ObjectID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			m.logError(ctx, "load object failed", err)
+		}
+		return false
+	}
+	return obj.ContentHash.Valid && obj.ContentHash.String == hash
+}
+
+func (m *Manager) saveContentHash(ctx context.Context, objectID *uuid.UUID, hash string) error {
+	err := database.New(m.db).UpdateObjectContentHash(ctx, database.UpdateObjectContentHashParams{
+		ID:          objectID,
+		ContentHash: sql.NullString{String: hash, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("save content hash for object %s: %w", objectID, err)
+	}
+	return nil
+}
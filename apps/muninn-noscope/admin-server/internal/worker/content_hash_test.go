@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestContentHash(t *testing.T) {
+	type content struct {
+		typeValues string
+		tags       []string
+	}
+	tests := []struct {
+		name  string
+		a, b  content
+		equal bool
+	}{
+		{
+			name:  "tag order ignored",
+			a:     content{`{"name":"Acme"}`, []string{"defi", "ai", "nft"}},
+			b:     content{`{"name":"Acme"}`, []string{"nft", "defi", "ai"}},
+			equal: true,
+		},
+		{
+			name:  "nil and empty tags",
+			a:     content{`{"name":"Acme"}`, nil},
+			b:     content{`{"name":"Acme"}`, []string{}},
+			equal: true,
+		},
+		{
+			name: "type values differ",
+			a:    content{`{"name":"Acme"}`, []string{"ai"}},
+			b:    content{`{"name":"Acme Inc"}`, []string{"ai"}},
+		},
+		{
+			name: "tag added",
+			a:    content{`{"name":"Acme"}`, []string{"ai"}},
+			b:    content{`{"name":"Acme"}`, []string{"ai", "defi"}},
+		},
+		{
+			name: "tag boundaries kept",
+			a:    content{`{"name":"Acme"}`, []string{"ab", "c"}},
+			b:    content{`{"name":"Acme"}`, []string{"a", "bc"}},
+		},
+		{
+			name: "type values and tags kept apart",
+			a:    content{`{"name":"Acme"}`, []string{"ai"}},
+			b:    content{`{"name":"Acme"}ai`, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := append([]string(nil), tt.a.tags...)
+			a := contentHash(json.RawMessage(tt.a.typeValues), tt.a.tags)
+			b := contentHash(json.RawMessage(tt.b.typeValues), tt.b.tags)
+			if (a == b) != tt.equal {
+				t.Errorf("contentHash equal = %v, want %v (%s vs %s)", a == b, tt.equal, a, b)
+			}
+			if !reflect.DeepEqual(tt.a.tags, tags) {
+				t.Errorf("contentHash reordered its tags argument to %v", tt.a.tags)
+			}
+		})
+	}
+}
@@ -2,13 +2,14 @@ package worker
 
 import (
 	"admin-server/internal/database"
+	"admin-server/internal/metrics"
+	"admin-server/internal/secrets"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/google/uuid"
@@ -24,24 +25,25 @@ type MuninnUpsertRequest struct {
 func (m *Manager) callNoscope(ctx context.Context, task *database.UpdateTaskProcessingRow) (*json.RawMessage, error) {
 	requestBody := map[string]interface{}{
 		"input": task.Input,
-		"data_models": []string{"name", "github", "labels", "caption", "summary", "linkedin", "framework", "blockchain", "product_category", "professional_dev", "organisation", "organisation_url"},
+		"data_models": m.settings.Current().DataModels,
 	}
 
 	// Create request to NOSCOPE_ENRICH_URL
 	requestBodyBytes, err := json.Marshal(requestBody)
 	if err != nil {
-		errMsg := err.Error()
-		m.updateTaskStatus(*task, "failed", nil, &errMsg)
 		return nil, fmt.Errorf("marshal noscope request: %w", err)
 	}
-	req, err := http.NewRequestWithContext(ctx, "POST", os.Getenv("NOSCOPE_ENRICH_URL"), bytes.NewReader(requestBodyBytes))
+	req, err := http.NewRequestWithContext(metrics.WithUpstream(ctx, "noscope"), "POST", m.secrets.Get(secrets.NoscopeEnrichURL), bytes.NewReader(requestBodyBytes))
 	if err != nil {
 			return nil, fmt.Errorf("create noscope request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", os.Getenv("NOSCOPE_KEY"))
+	req.Header.Set("x-api-key", m.secrets.Get(secrets.NoscopeKey))
 
+	if err := m.noscopeLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("wait for noscope rate limit: %w", err)
+	}
 	resp, err := m.client.Do(req)
 	if err != nil {
 			return nil, fmt.Errorf("execute noscope request: %w", err)
@@ -64,7 +66,7 @@ func (m *Manager) callNoscope(ctx context.Context, task *database.UpdateTaskProc
 func (m *Manager) callMuninnUpsert(ctx context.Context, task *database.UpdateTaskProcessingRow, typeValues json.RawMessage) error {
 	muninnReq := MuninnUpsertRequest{
 		ObjectID:     *task.ObjectID,
-		ObjectTypeID: os.Getenv("MUNINN_NOSCOPE_OBJTYPE_ID"),
+		ObjectTypeID: m.secrets.Get(secrets.MuninnNoscopeObjTypeID),
 		TypeValues:   typeValues,
 	}
 
@@ -73,15 +75,14 @@ func (m *Manager) callMuninnUpsert(ctx context.Context, task *database.UpdateTas
 		return fmt.Errorf("marshal muninn request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", os.Getenv("MUNINN_UPSERT_OBJTYPE_URL"), bytes.NewReader(reqBody))
+	req, err := http.NewRequestWithContext(metrics.WithUpstream(ctx, "muninn_upsert"), "POST", m.secrets.Get(secrets.MuninnUpsertObjTypeURL), bytes.NewReader(reqBody))
 	if err != nil {
 			return fmt.Errorf("create muninn request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("MUNINN_JWT")))
 
-	resp, err := m.client.Do(req)
+	resp, err := m.muninn.Do(req)
 	if err != nil {
 			return fmt.Errorf("execute muninn request: %w", err)
 	}
@@ -101,10 +102,36 @@ func (m *Manager) callMuninnUpsert(ctx context.Context, task *database.UpdateTas
 
 // Add this structure for the Noscope response and Muninn tag request
 type NoscopeResponse struct {
-	Labels string       `json:"labels"`
+	Labels          TagField `json:"labels"`
+	Blockchain      TagField `json:"blockchain"`
+	Framework       TagField `json:"framework"`
+	ProductCategory TagField `json:"product_category"`
 	// other fields that might be in the response...
 }
 
+// TagField is a tag-bearing Noscope field. It accepts the same shapes as a string
+// field in the mapping, so ["sol","eth"] reads as "sol, eth" and null as empty.
+type TagField string
+
+func (f *TagField) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	if value == nil {
+		*f = ""
+		return nil
+	}
+	s, err := coerceString(value)
+	if err != nil {
+		return err
+	}
+	*f = TagField(s)
+	return nil
+}
+
 type MuninnTagRequest struct {
 	ObjectID uuid.UUID `json:"object_id"`
 	Tags     []string  `json:"tags"`
@@ -114,21 +141,79 @@ func Split(r rune) bool {
 	return r == ',' || r == '#' || r == '@' || r == ';'
 }
 
-// Add the new function to call Muninn tag API
-func (m *Manager) callMuninnTagObject(ctx context.Context, objID uuid.UUID, noscopeResp json.RawMessage) error {
+// buildTags extracts and normalises the tag set for a Noscope response
+func (m *Manager) buildTags(ctx context.Context, noscopeResp json.RawMessage) ([]string, error) {
 	// Parse the Noscope response to get labels
 	var noscope NoscopeResponse
 	if err := json.Unmarshal(noscopeResp, &noscope); err != nil {
-		return fmt.Errorf("parse noscope response: %w", err)
+		return nil, fmt.Errorf("parse noscope response: %w", err)
 	}
 
-	// Skip if no labels
-	if noscope.Labels == "" {
-		return nil
+	normalizer, err := m.loadTagNormalizer(ctx)
+	if err != nil {
+		return nil, err
 	}
-	// split noscope.Labels into tags
-	tags := strings.FieldsFunc(noscope.Labels, Split)
+	return normalizer.Normalize(noscope.RawTags()), nil
+}
+
+// MuninnObject is the part of Muninn's object detail response the worker reads.
+// Tags arrive either as names or as objects carrying a name.
+type MuninnObject struct {
+	Tags []json.RawMessage `json:"tags"`
+}
 
+// fetchMuninnTags returns the tags currently on an object in Muninn, whoever added them
+func (m *Manager) fetchMuninnTags(ctx context.Context, objID uuid.UUID) ([]string, error) {
+	url := strings.TrimSuffix(m.secrets.Get(secrets.MuninnObjectURL), "/") + "/" + objID.String()
+	req, err := http.NewRequestWithContext(metrics.WithUpstream(ctx, "muninn_object"), "GET", url, nil)
+	if err != nil {
+			return nil, fmt.Errorf("create object request: %w", err)
+	}
+
+	resp, err := m.muninn.Do(req)
+	if err != nil {
+			return nil, fmt.Errorf("execute object request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+			return nil, fmt.Errorf("read object response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("muninn object API returned status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var object MuninnObject
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, fmt.Errorf("parse object response: %w", err)
+	}
+	tags := make([]string, 0, len(object.Tags))
+	for _, raw := range object.Tags {
+		var name string
+		if err := json.Unmarshal(raw, &name); err != nil {
+			var tag struct {
+				Name string `json:"name"`
+				Tag  string `json:"tag"`
+			}
+			if err := json.Unmarshal(raw, &tag); err != nil {
+				return nil, fmt.Errorf("parse object tag %s: %w", raw, err)
+			}
+			name = tag.Name
+			if name == "" {
+				name = tag.Tag
+			}
+		}
+		if name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags, nil
+}
+
+// callMuninnTagAPI posts a tag set to either the tag or untag endpoint
+func (m *Manager) callMuninnTagAPI(ctx context.Context, url string, objID uuid.UUID, tags []string) error {
 	// Prepare tag request
 	tagReq := MuninnTagRequest{
 		ObjectID: objID,
@@ -140,15 +225,14 @@ func (m *Manager) callMuninnTagObject(ctx context.Context, objID uuid.UUID, nosc
 			return fmt.Errorf("marshal tag request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", os.Getenv("MUNINN_TAGS_OBJ_URL"), bytes.NewReader(reqBody))
+	req, err := http.NewRequestWithContext(metrics.WithUpstream(ctx, "muninn_tag"), "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
 			return fmt.Errorf("create tag request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("MUNINN_JWT")))
 
-	resp, err := m.client.Do(req)
+	resp, err := m.muninn.Do(req)
 	if err != nil {
 			return fmt.Errorf("execute tag request: %w", err)
 	}
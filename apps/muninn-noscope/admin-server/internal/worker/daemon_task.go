@@ -2,45 +2,68 @@ package worker
 
 import (
 	"admin-server/internal/database"
+	"admin-server/internal/logging"
+	"admin-server/internal/metrics"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"log"
-	"os"
-	"strconv"
 	"time"
 
 	"github.com/sqlc-dev/pqtype"
 )
 
 func (m *Manager) processLoop() {
-	sleepSeconds, err := strconv.Atoi(os.Getenv("TASK_SLEEP_IN_SECONDS"))
-	if err != nil {
-		log.Fatalf("Invalid TASK_SLEEP_IN_SECONDS: %v", err)
-	}
-	ticker := time.NewTicker(time.Duration(sleepSeconds) * time.Second)
+	pollInterval := m.settings.Current().PollInterval
+	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-m.ctx.Done():
-			log.Printf("Worker %s received shutdown signal", m.workerID)
+			m.logger.InfoContext(m.ctx, "worker received shutdown signal")
 			return
+		case <-m.pollChanged:
+			if next := m.settings.Current().PollInterval; next != pollInterval {
+				pollInterval = next
+				ticker.Reset(pollInterval)
+			}
 		case <-ticker.C:
+			if m.processorStatus() == StatusPaused {
+				continue
+			}
 			if err := m.processPendingTasks(); err != nil {
-				m.logError(fmt.Sprintf("Error processing pending tasks: %v", err))
+				m.logError(m.ctx, "process pending tasks failed", err)
 			}
 		}
 	}
 }
 
+// processPendingTasks claims pending tasks until pool_size are in flight or none are left
 func (m *Manager) processPendingTasks() error {
+	poolSize := m.settings.Current().PoolSize
+	for {
+		m.metrics.Lock()
+		full := m.metrics.CurrentTasks >= poolSize
+		m.metrics.Unlock()
+		if full {
+			return nil
+		}
+		claimed, err := m.claimTask()
+		if err != nil || !claimed {
+			return err
+		}
+	}
+}
+
+// claimTask marks one pending task as processing and starts it, reporting whether there was one
+func (m *Manager) claimTask() (bool, error) {
     // Begin transaction
     tx, err := m.db.BeginTx(m.ctx, &sql.TxOptions{
 			Isolation: sql.LevelReadCommitted,
     })
     if err != nil {
-			return fmt.Errorf("start transaction: %w", err)
+			return false, fmt.Errorf("start transaction: %w", err)
     }
     defer tx.Rollback()
 
@@ -50,23 +73,26 @@ func (m *Manager) processPendingTasks() error {
     // Find and lock a pending task
 
     if err == sql.ErrNoRows {
-			return nil
+			return false, nil
     }
     if err != nil {
-			return fmt.Errorf("query task: %w", err)
+			return false, fmt.Errorf("query task: %w", err)
     }
 
     // Commit transaction to release lock
     if err := tx.Commit(); err != nil {
-			return fmt.Errorf("commit transaction: %w", err)
+			return false, fmt.Errorf("commit transaction: %w", err)
     }
 
     // Update metrics for currently processing tasks
     m.metrics.Lock()
     m.metrics.CurrentTasks++
     m.metrics.Unlock()
+    metrics.TasksInFlight.WithLabelValues(m.workerID).Inc()
 
-    // Process task in background
+    // Process task in background, under the task context so that pausing or
+    // draining the loop lets it finish
+    taskCtx := m.taskCtx
     m.processingWg.Add(1)
     go func() {
 			defer m.processingWg.Done()
@@ -74,50 +100,108 @@ func (m *Manager) processPendingTasks() error {
 				m.metrics.Lock()
 				m.metrics.CurrentTasks--
 				m.metrics.Unlock()
+				metrics.TasksInFlight.WithLabelValues(m.workerID).Dec()
 			}()
-			m.processTask(&task)
+			start := time.Now()
+			ctx := logging.With(taskCtx, "task_id", task.ID, "object_id", task.ObjectID)
+			m.processTask(ctx, &task)
+			metrics.TaskDuration.WithLabelValues(m.workerID).Observe(time.Since(start).Seconds())
     }()
 
-    return nil
+    return true, nil
 }
 
-func (m *Manager) processTask(task *database.UpdateTaskProcessingRow) {
+func (m *Manager) processTask(ctx context.Context, task *database.UpdateTaskProcessingRow) {
+	m.logger.InfoContext(ctx, "processing task")
+
 	m.metrics.Lock()
 	m.metrics.TasksProcessed++
 	m.metrics.Unlock()
+	metrics.TasksProcessed.WithLabelValues(m.workerID).Inc()
 
 	// Call Noscope API
-	noscopeResp, err := m.callNoscope(m.ctx, task)
+	noscopeResp, err := m.callNoscope(ctx, task)
 	if err != nil {
 			errMsg := err.Error()
-			m.updateTaskStatus(*task, "failed", nil, &errMsg)
+			m.updateTaskStatus(ctx, *task, "failed", nil, &errMsg, errClassNoscope)
+			return
+	}
+
+	// Map Noscope response into Muninn type_values
+	typeValues, err := m.mapping.Apply(*noscopeResp)
+	if err != nil {
+			errMsg := fmt.Sprintf("Noscope response mapping failed: %v", err)
+			noscopeRespBytes := []byte(*noscopeResp)
+			m.updateTaskStatus(ctx, *task, "failed", &noscopeRespBytes, &errMsg, errClassMapping)
 			return
 	}
 
-	// Call Muninn API with Noscope response
-	if err := m.callMuninnUpsert(m.ctx, task, *noscopeResp); err != nil {
+	noscopeRespBytes := []byte(*noscopeResp)
+
+	// Build the tag set up front so it is part of the content hash
+	tags, tagErr := m.buildTags(ctx, *noscopeResp)
+
+	// Skip the Muninn writes when nothing changed since the last applied result
+	hash := contentHash(typeValues, tags)
+	if tagErr == nil && m.isUnchanged(ctx, task, hash) {
+		m.updateTaskStatus(ctx, *task, "unchanged", &noscopeRespBytes, nil, "")
+		return
+	}
+
+	// Call Muninn API with mapped type_values
+	if err := m.callMuninnUpsert(ctx, task, typeValues); err != nil {
 			errMsg := fmt.Sprintf("Muninn upsert failed: %v", err)
 			// We still save the Noscope response even if Muninn fails
-			noscopeRespBytes := []byte(*noscopeResp)
-			m.updateTaskStatus(*task, "failed", &noscopeRespBytes, &errMsg)
+			m.updateTaskStatus(ctx, *task, "failed", &noscopeRespBytes, &errMsg, errClassMuninnUpsert)
 			return
 	}
 
-	// Call Muninn Tag API with Noscope response
-	if err := m.callMuninnTagObject(m.ctx, *task.ObjectID, *noscopeResp); err != nil {
-		errMsg := fmt.Sprintf("Muninn tag failed: %v", err)
-		noscopeRespBytes := []byte(*noscopeResp)
-		// We still consider the task completed since tagging is optional
-		m.updateTaskStatus(*task, "completed", &noscopeRespBytes, &errMsg)
+	// Sync Muninn tags with the normalised tag set
+	if tagErr == nil {
+		tagErr = m.syncObjectTags(ctx, *task.ObjectID, tags)
+	}
+	if tagErr != nil {
+		errMsg := fmt.Sprintf("Muninn tag failed: %v", tagErr)
+		// History records the tags that are actually on the object after the partial sync
+		appliedTags, err := database.New(m.db).ListObjectTags(ctx, task.ObjectID)
+		if err != nil {
+			m.logError(ctx, "list applied tags failed", err)
+		}
+		if _, err := m.recordEnrichment(ctx, task.ObjectID, task.ID, typeValues, appliedTags, m.mappingVersion()); err != nil {
+			m.logError(ctx, "record enrichment failed", err)
+		}
+		// We still consider the task completed since tagging is optional,
+		// but leave the hash alone so the next refresh retries the tags
+		m.updateTaskStatus(ctx, *task, "completed", &noscopeRespBytes, &errMsg, "")
 		return
-}
+	}
+
+	if _, err := m.recordEnrichment(ctx, task.ObjectID, task.ID, typeValues, tags, m.mappingVersion()); err != nil {
+		m.logError(ctx, "record enrichment failed", err)
+	}
+	if err := m.saveContentHash(ctx, task.ObjectID, hash); err != nil {
+		m.logError(ctx, "save content hash failed", err)
+	}
 
 	// Update task as completed with Noscope response
-	noscopeRespBytes := []byte(*noscopeResp)
-	m.updateTaskStatus(*task, "completed", &noscopeRespBytes, nil)
+	m.updateTaskStatus(ctx, *task, "completed", &noscopeRespBytes, nil, "")
 }
 
-func (m *Manager) updateTaskStatus(task database.UpdateTaskProcessingRow, status string, output *[]byte, errorMsg *string) {
+// Error classes label failed tasks in metrics by the step that failed
+const (
+	errClassNoscope      = "noscope"
+	errClassMapping      = "mapping"
+	errClassMuninnUpsert = "muninn_upsert"
+)
+
+func (m *Manager) updateTaskStatus(ctx context.Context, task database.UpdateTaskProcessingRow, status string, output *[]byte, errorMsg *string, errClass string) {
+	// A hard stop or drain deadline cancelled the task part way through, so its
+	// outcome says nothing about the object; leave it for the next run
+	if ctx.Err() != nil {
+		m.requeueTask(ctx, task)
+		return
+	}
+
 	now := time.Now()
 
 	var outputJSON sql.NullString
@@ -136,30 +220,68 @@ func (m *Manager) updateTaskStatus(task database.UpdateTaskProcessingRow, status
 		}
 	}
 
+	// Only a task that got a Noscope response reached the mapping, so a
+	// Noscope failure records no mapping version
+	var mappingVersion sql.NullString
+	if output != nil {
+		mappingVersion = m.mappingVersion()
+	}
+
 	queries := database.New(m.db)
-	err := queries.UpdateTaskStatus(m.ctx, database.UpdateTaskStatusParams{
+	err := queries.UpdateTaskStatus(ctx, database.UpdateTaskStatusParams{
 		Status:      status,
 		Output:      pqtype.NullRawMessage{RawMessage: json.RawMessage(outputJSON.String), Valid: outputJSON.Valid},
 		Error:       errorNullString,
 		CompletedAt: sql.NullTime{Time: now, Valid: true},
+		MappingVersion: mappingVersion,
 		ID:          task.ID,
 	});
 
 	if err != nil {
-		m.logError(fmt.Sprintf("Error updating task status: %v", err))
+		m.logError(ctx, "update task status failed", err)
 		return
 	}
 
-	queries.UpdateObjectLastSyncedAt(m.ctx, database.UpdateObjectLastSyncedAtParams{
+	queries.UpdateObjectLastSyncedAt(ctx, database.UpdateObjectLastSyncedAtParams{
 		ID: task.ObjectID,
 		LastSyncedAt: sql.NullTime{Time: now, Valid: true},
 	});
 
+	if errorMsg != nil {
+		m.logger.WarnContext(ctx, "task finished", "status", status, "error_class", errClass, "error", *errorMsg)
+	} else {
+		m.logger.InfoContext(ctx, "task finished", "status", status)
+	}
+
 	m.metrics.Lock()
-	if status == "completed" {
+	switch status {
+	case "completed":
 		m.metrics.TasksSucceeded++
-	} else {
+		metrics.TasksSucceeded.WithLabelValues(m.workerID).Inc()
+	case "unchanged":
+		m.metrics.TasksUnchanged++
+		metrics.TasksUnchanged.WithLabelValues(m.workerID).Inc()
+	default:
 		m.metrics.TasksFailed++
+		metrics.TasksFailed.WithLabelValues(m.workerID, errClass).Inc()
 	}
 	m.metrics.Unlock()
 }
+
+// requeueTask puts a cancelled task back to pending
+func (m *Manager) requeueTask(ctx context.Context, task database.UpdateTaskProcessingRow) {
+	// ctx is already cancelled, but the update must still reach the database
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := database.New(m.db).RequeueTask(ctx, task.ID); err != nil {
+		m.logError(ctx, "requeue cancelled task failed", err)
+		return
+	}
+	m.logger.WarnContext(ctx, "task cancelled, requeued")
+
+	m.metrics.Lock()
+	m.metrics.TasksRequeued++
+	m.metrics.Unlock()
+	metrics.TasksRequeued.WithLabelValues(m.workerID).Inc()
+}
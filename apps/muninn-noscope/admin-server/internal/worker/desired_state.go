@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"admin-server/internal/settings"
+)
+
+// reconcileInterval is how often the components are checked against the desired
+// state even without a change, so a start that failed, e.g. on a missing secret,
+// is retried and a drain that ended is followed by a requested start
+const reconcileInterval = 30 * time.Second
+
+// Reconcile brings this process's components to the desired state in the
+// settings. Components this process does not run are left alone.
+func (m *Manager) Reconcile() error {
+	m.reconcileMu.Lock()
+	defer m.reconcileMu.Unlock()
+	if m.reconcileDone {
+		return nil
+	}
+
+	desired := m.settings.Current()
+	var errs []error
+	if err := m.reconcileProcessor(desired.Processor); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.reconcileScheduler(desired.Scheduler); err != nil {
+		errs = append(errs, err)
+	}
+	for _, t := range m.scheduler.Tasks() {
+		// Scheduled tasks of this process only; names of other tasks are ignored
+		paused := slices.Contains(desired.PausedScheduledTasks, t.Name)
+		if paused != t.Paused {
+			if paused {
+				errs = append(errs, m.PauseScheduledTask(t.Name))
+			} else {
+				errs = append(errs, m.ResumeScheduledTask(t.Name))
+			}
+		}
+	}
+	err := errors.Join(errs...)
+	if err != nil {
+		m.applyError.Store(err.Error())
+	} else {
+		m.applyError.Store("")
+	}
+	return err
+}
+
+func (m *Manager) reconcileProcessor(desired settings.ProcessorState) error {
+	switch status := m.processorStatus(); {
+	case status == StatusDisabled:
+		return nil
+	case status == StatusDraining:
+		// A drain cannot be undone. It finishes on its own; a stop without a
+		// drain deadline cuts it short, and a start waits until it has finished.
+		if desired.State == settings.StateStopped && desired.DrainUntil == nil {
+			return ignoreAlready(m.StopProcessor())
+		}
+		return nil
+	case desired.State == settings.StateStopped:
+		if status == StatusStopped {
+			return nil
+		}
+		if desired.DrainUntil != nil {
+			if remaining := time.Until(*desired.DrainUntil); remaining > 0 {
+				return ignoreAlready(m.Drain(remaining))
+			}
+		}
+		return ignoreAlready(m.StopProcessor())
+	case status == StatusStopped:
+		if err := ignoreAlready(m.StartProcessor()); err != nil {
+			return err
+		}
+		if desired.State == settings.StatePaused {
+			return m.Pause()
+		}
+		return nil
+	case desired.State == settings.StatePaused && status == StatusRunning:
+		return m.Pause()
+	case desired.State == settings.StateRunning && status == StatusPaused:
+		return m.Resume()
+	}
+	return nil
+}
+
+func (m *Manager) reconcileScheduler(desired string) error {
+	var err error
+	if desired == settings.StateStopped {
+		err = m.StopScheduler()
+	} else {
+		err = m.StartScheduler()
+	}
+	if errors.Is(err, ErrDisabled) {
+		return nil
+	}
+	return ignoreAlready(err)
+}
+
+// ignoreAlready drops the error for a component that is already in the requested state
+func ignoreAlready(err error) error {
+	var already *alreadyError
+	if errors.As(err, &already) {
+		return nil
+	}
+	return err
+}
+
+// reconcileLoop applies the desired state on every settings change and every
+// reconcileInterval until ctx is cancelled. Repeated failures are logged once.
+func (m *Manager) reconcileLoop(ctx context.Context) {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+	var lastErr string
+	for {
+		if err := m.Reconcile(); err != nil {
+			if err.Error() != lastErr {
+				m.logger.Error("apply desired worker state failed", "error", err)
+			}
+			lastErr = err.Error()
+		} else {
+			lastErr = ""
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.desiredChanged:
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"admin-server/internal/database"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// FieldChange is one type_values difference between consecutive enrichment snapshots
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Change string      `json:"change"`
+	Old    interface{} `json:"old,omitempty"`
+	New    interface{} `json:"new,omitempty"`
+}
+
+// SnapshotDiff describes what a snapshot changed relative to the one before it
+type SnapshotDiff struct {
+	Fields      []FieldChange `json:"fields"`
+	TagsAdded   []string      `json:"tags_added"`
+	TagsRemoved []string      `json:"tags_removed"`
+}
+
+// DiffSnapshots compares two enrichment snapshots field by field. prev may be nil for the first version.
+func DiffSnapshots(prev, next *database.ObjectEnrichment) (*SnapshotDiff, error) {
+	oldValues := map[string]interface{}{}
+	var oldTags []string
+	if prev != nil {
+		if err := json.Unmarshal(prev.TypeValues, &oldValues); err != nil {
+			return nil, fmt.Errorf("parse version %d: %w", prev.Version, err)
+		}
+		oldTags = prev.Tags
+	}
+	newValues := map[string]interface{}{}
+	if err := json.Unmarshal(next.TypeValues, &newValues); err != nil {
+		return nil, fmt.Errorf("parse version %d: %w", next.Version, err)
+	}
+
+	fields := make([]string, 0, len(oldValues)+len(newValues))
+	for field := range oldValues {
+		fields = append(fields, field)
+	}
+	for field := range newValues {
+		if _, ok := oldValues[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	diff := &SnapshotDiff{Fields: []FieldChange{}}
+	for _, field := range fields {
+		oldValue, hadOld := oldValues[field]
+		newValue, hasNew := newValues[field]
+		switch {
+		case !hadOld:
+			diff.Fields = append(diff.Fields, FieldChange{Field: field, Change: "added", New: newValue})
+		case !hasNew:
+			diff.Fields = append(diff.Fields, FieldChange{Field: field, Change: "removed", Old: oldValue})
+		case !sameJSON(oldValue, newValue):
+			diff.Fields = append(diff.Fields, FieldChange{Field: field, Change: "changed", Old: oldValue, New: newValue})
+		}
+	}
+
+	diff.TagsAdded, diff.TagsRemoved = diffTags(oldTags, next.Tags)
+	return diff, nil
+}
+
+func sameJSON(a, b interface{}) bool {
+	aBytes, aErr := json.Marshal(a)
+	bBytes, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aBytes, bBytes)
+}
+
+// recordEnrichment appends a new history version for the object. The object row
+// is locked while the next version number is chosen, so a rollback and a task
+// for the same object cannot both take it.
+func (m *Manager) recordEnrichment(ctx context.Context, objectID, taskID *uuid.UUID, typeValues json.RawMessage, tags []string, mappingVersion sql.NullString) (*database.ObjectEnrichment, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := database.New(m.db).WithTx(tx)
+	if _, err := queries.LockObjectForEnrichment(ctx, objectID); err != nil {
+		return nil, fmt.Errorf("lock object %s: %w", objectID, err)
+	}
+	snapshot, err := queries.CreateObjectEnrichment(ctx, database.CreateObjectEnrichmentParams{
+		ObjectID:       objectID,
+		TaskID:         taskID,
+		TypeValues:     typeValues,
+		Tags:           tags,
+		ContentHash:    contentHash(typeValues, tags),
+		MappingVersion: mappingVersion,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("record enrichment history for object %s: %w", objectID, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit enrichment history for object %s: %w", objectID, err)
+	}
+	return &snapshot, nil
+}
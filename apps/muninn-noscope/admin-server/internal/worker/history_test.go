@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"admin-server/internal/database"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	snapshot := func(version int32, typeValues string, tags ...string) *database.ObjectEnrichment {
+		return &database.ObjectEnrichment{Version: version, TypeValues: json.RawMessage(typeValues), Tags: tags}
+	}
+
+	tests := []struct {
+		name       string
+		prev, next *database.ObjectEnrichment
+		want       *SnapshotDiff
+		wantErr    bool
+	}{
+		{
+			name: "first version adds everything",
+			next: snapshot(1, `{"name":"Acme","github":"https://github.com/acme"}`, "ai"),
+			want: &SnapshotDiff{
+				Fields: []FieldChange{
+					{Field: "github", Change: "added", New: "https://github.com/acme"},
+					{Field: "name", Change: "added", New: "Acme"},
+				},
+				TagsAdded: []string{"ai"},
+			},
+		},
+		{
+			name: "added, removed and changed fields sorted by name",
+			prev: snapshot(1, `{"name":"Acme","summary":"old","caption":"x"}`),
+			next: snapshot(2, `{"name":"Acme","summary":"new","labels":"a"}`),
+			want: &SnapshotDiff{
+				Fields: []FieldChange{
+					{Field: "caption", Change: "removed", Old: "x"},
+					{Field: "labels", Change: "added", New: "a"},
+					{Field: "summary", Change: "changed", Old: "old", New: "new"},
+				},
+			},
+		},
+		{
+			name: "nested values compared by content",
+			prev: snapshot(1, `{"meta":{"a":1,"b":[1,2]}}`),
+			next: snapshot(2, `{"meta":{"b":[1,2],"a":1}}`),
+			want: &SnapshotDiff{Fields: []FieldChange{}},
+		},
+		{
+			name: "tag changes",
+			prev: snapshot(1, `{}`, "ai", "defi"),
+			next: snapshot(2, `{}`, "defi", "nft"),
+			want: &SnapshotDiff{
+				Fields:      []FieldChange{},
+				TagsAdded:   []string{"nft"},
+				TagsRemoved: []string{"ai"},
+			},
+		},
+		{
+			name:    "invalid previous snapshot",
+			prev:    snapshot(1, `[`),
+			next:    snapshot(2, `{}`),
+			wantErr: true,
+		},
+		{
+			name:    "invalid next snapshot",
+			next:    snapshot(1, `"Acme"`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffSnapshots(tt.prev, tt.next)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DiffSnapshots() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffSnapshots() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
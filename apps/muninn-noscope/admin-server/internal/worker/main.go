@@ -2,18 +2,26 @@ package worker
 
 import (
 	"admin-server/internal/database"
+	"admin-server/internal/logging"
+	"admin-server/internal/metrics"
+	"admin-server/internal/muninn"
+	"admin-server/internal/secrets"
+	"admin-server/internal/settings"
 	task "admin-server/internal/worker/schedule_task"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/time/rate"
 )
 
 type Task struct {
@@ -32,167 +40,621 @@ type Metrics struct {
 	TasksProcessed   int64     `json:"tasks_processed"`
 	TasksSucceeded   int64     `json:"tasks_succeeded"`
 	TasksFailed      int64     `json:"tasks_failed"`
+	TasksUnchanged   int64     `json:"tasks_unchanged"`
+	TasksRequeued    int64     `json:"tasks_requeued"`
+	// WorkerStatus is the task processor's status
 	WorkerStatus     string    `json:"worker_status"`
+	SchedulerStatus  string    `json:"scheduler_status"`
 	LastStartTime    time.Time `json:"last_start_time,omitempty"`
 	LastErrorTime    time.Time `json:"last_error_time,omitempty"`
 	LastError        string    `json:"last_error,omitempty"`
 	CurrentTasks     int       `json:"current_tasks"`
+	// DrainStartedAt and DrainDeadline are set while the worker is draining
+	DrainStartedAt *time.Time `json:"drain_started_at,omitempty"`
+	DrainDeadline  *time.Time `json:"drain_deadline,omitempty"`
 	sync.Mutex
 }
 
+// Processor and scheduler statuses. A paused processor claims no new tasks but
+// finishes the ones in flight; a draining processor does the same and then
+// stops, cancelling whatever is left at its deadline. The scheduler is only ever
+// running or stopped. Disabled components cannot be started in this process.
+const (
+	StatusRunning  = "running"
+	StatusPaused   = "paused"
+	StatusDraining = "draining"
+	StatusStopped  = "stopped"
+	StatusDisabled = "disabled"
+)
+
+var statuses = []string{StatusRunning, StatusPaused, StatusDraining, StatusStopped, StatusDisabled}
+
+// ErrDisabled is returned when starting a component this process does not run
+var ErrDisabled = errors.New("disabled in this process")
+
+// alreadyError reports a component that is already in the requested state
+type alreadyError struct {
+	component string
+	status    string
+}
+
+func (e *alreadyError) Error() string {
+	return fmt.Sprintf("%s is already %s", e.component, e.status)
+}
+
+// Options are the worker settings read from configuration that cannot change at
+// runtime; the rest come from a settings.Store
+type Options struct {
+	// WorkerID labels this worker's metrics; the hostname is used when it is empty
+	WorkerID string
+	MaxTags  int
+	// Processor and Scheduler choose which components this process may run, so
+	// processing and scanning can be deployed separately
+	Processor bool
+	Scheduler bool
+}
+
+// Status is the state of both components in this process, for GET /api/worker/status
+type Status struct {
+	Processor ProcessorStatus `json:"processor"`
+	Scheduler SchedulerStatus `json:"scheduler"`
+	// ApplyError is why the last attempt to reach the desired state failed
+	ApplyError string `json:"apply_error,omitempty"`
+}
+
+type ProcessorStatus struct {
+	Status        string     `json:"status"`
+	Desired       settings.ProcessorState `json:"desired"`
+	InFlight      int        `json:"in_flight"`
+	DrainDeadline *time.Time `json:"drain_deadline,omitempty"`
+}
+
+type SchedulerStatus struct {
+	Status string                `json:"status"`
+	Desired string               `json:"desired"`
+	// PausedTasks are the scheduled tasks that should not run
+	PausedTasks []string         `json:"paused_tasks"`
+	Tasks  []ScheduledTaskStatus `json:"tasks"`
+}
+
 type Manager struct {
 	db           *sql.DB
 	client       *http.Client
+	muninn       *muninn.Client
+	// workerID labels this worker's metrics and stays the same across restarts;
+	// instanceID is unique per process and only ties its log lines together
 	workerID     string
+	instanceID   string
 	processingWg sync.WaitGroup
 	metrics      *Metrics
+	// ctx covers claiming; taskCtx covers tasks already claimed, so stopping
+	// the loop does not abort them
 	cancel       context.CancelFunc
 	ctx          context.Context
-	isRunning    bool
+	cancelTasks  context.CancelFunc
+	taskCtx      context.Context
+	// loopWg tracks processLoop; processingWg tracks tasks
+	loopWg       sync.WaitGroup
+	// status is the processor's status
+	status       string
+	// done is closed when the current processor run has stopped
+	done         chan struct{}
 	mu           sync.Mutex
 	scheduler		*Scheduler
+	// schedulerEnabled is Options.Scheduler; schedulerDone is non-nil while the
+	// scheduler runs and closed once it has stopped
+	schedulerEnabled bool
+	schedulerCancel  context.CancelFunc
+	schedulerDone    chan struct{}
+	logger       *slog.Logger
+	secrets      *secrets.Store
+	settings     *settings.Store
+	// noscopeLimiter applies the noscope_rate_limit setting
+	noscopeLimiter *rate.Limiter
+	// pollChanged wakes processLoop when poll_interval changes
+	pollChanged chan struct{}
+	// desiredChanged wakes reconcileLoop when settings change; reconcileMu
+	// keeps reconciles from interleaving
+	desiredChanged chan struct{}
+	reconcileMu    sync.Mutex
+	// applyError holds the last Reconcile failure as a string; reconcileDone is
+	// set once Run is shutting down, after which Reconcile does nothing
+	applyError    atomic.Value
+	reconcileDone bool
+	mapping      *Mapping
+	maxTags      int
 }
 
-func NewManager(db *sql.DB, logger *log.Logger) *Manager {
+func NewManager(db *sql.DB, secretStore *secrets.Store, settingsStore *settings.Store, opts Options, logger *slog.Logger) *Manager {
+	current := settingsStore.Current()
+	// Upserts, tagging and scans share one client so they share its cached token and rate limit
+	muninnClient := muninn.NewClient(secretStore, logger.With("component", "muninn"))
+	muninnClient.SetRateLimit(settings.RateLimit(current.MuninnRateLimit))
 	mrg := &Manager{
 		db: db,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: metrics.InstrumentTransport(nil),
 		},
-		workerID: uuid.New().String(),
-		metrics: &Metrics{
-			WorkerStatus: "stopped",
-		},
-		isRunning: false,
+		muninn:     muninnClient,
+		workerID:   resolveWorkerID(opts.WorkerID),
+		instanceID: uuid.New().String(),
+		logger:     logger,
+		secrets:    secretStore,
+		settings:   settingsStore,
+		noscopeLimiter: rate.NewLimiter(settings.RateLimit(current.NoscopeRateLimit), 1),
+		pollChanged: make(chan struct{}, 1),
+		desiredChanged: make(chan struct{}, 1),
+		metrics: &Metrics{},
+		status:  StatusStopped,
+		schedulerEnabled: opts.Scheduler,
+		mapping: DefaultMapping,
+		maxTags:   opts.MaxTags,
 	}
 
 	// Initialize scheduler
-	scheduler := NewScheduler(logger)
+	scheduler := NewScheduler(logger.With("component", "scheduler"))
 	scanTask := task.NewScanTask(
 		database.New(db), 
-		&http.Client{Timeout: 30 * time.Second}, 
-		log.New(os.Stdout, "scheduler: ", log.LstdFlags),
+		muninnClient,
+		secretStore,
+		settingsStore,
+		logger.With("component", "scan_task"),
 	)
-	// Add scan task to run every 
-	// 5 minute in production
-	scheduler.AddTask("object-scan", scanTask, 5*time.Minute)
+	scheduler.AddTask("object-scan", scanTask, current.ScanInterval)
 
 	mrg.scheduler = scheduler
+	if !opts.Processor {
+		mrg.status = StatusDisabled
+	}
+	mrg.setStatus(mrg.status)
+	mrg.setSchedulerStatus()
+
+	// Apply setting changes to the running worker
+	settingsStore.Subscribe(func(s settings.Settings) {
+		scheduler.SetInterval("object-scan", s.ScanInterval)
+		mrg.noscopeLimiter.SetLimit(settings.RateLimit(s.NoscopeRateLimit))
+		muninnClient.SetRateLimit(settings.RateLimit(s.MuninnRateLimit))
+		select {
+		case mrg.pollChanged <- struct{}{}:
+		default:
+		}
+		select {
+		case mrg.desiredChanged <- struct{}{}:
+		default:
+		}
+	})
 
 	return mrg;
 }
 
-func (m *Manager) Start() error {
+// resolveWorkerID names the worker in metric labels. A fresh id per start would
+// leave a new set of series behind on every restart, so it is configured or
+// falls back to the hostname instead.
+func resolveWorkerID(id string) string {
+	if id != "" {
+		return id
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "worker"
+}
+
+// StartProcessor starts claiming and processing pending tasks. It and the other
+// lifecycle methods act on this process only; Reconcile applies the desired
+// state shared by every process through them.
+func (m *Manager) StartProcessor() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if m.isRunning {
-		return fmt.Errorf("worker is already running")
+	switch m.status {
+	case StatusStopped:
+	case StatusDisabled:
+		return fmt.Errorf("processor is %w", ErrDisabled)
+	default:
+		return &alreadyError{"processor", m.status}
 	}
 
   // Validate configuration before starting
-	if err := m.validateConfig(); err != nil {
+	if err := m.validateConfig(processorSecrets); err != nil {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
-	// Create new context for this run
-	ctx, cancel := context.WithCancel(context.Background())
-	m.ctx = ctx
-	m.cancel = cancel
-	m.isRunning = true
+	// Create new contexts for this run
+	base := logging.With(context.Background(), "worker_id", m.workerID, "instance_id", m.instanceID)
+	m.ctx, m.cancel = context.WithCancel(base)
+	m.taskCtx, m.cancelTasks = context.WithCancel(base)
+	m.done = make(chan struct{})
 
 	// Update metrics
 	m.metrics.Lock()
-	m.metrics.WorkerStatus = "running"
 	m.metrics.LastStartTime = time.Now()
 	m.metrics.Unlock()
+	m.setStatus(StatusRunning)
 
 	// Start processing in background
-	go m.processLoop()
-	go m.scheduler.Start(m.ctx)
+	m.loopWg.Add(1)
+	go func() {
+		defer m.loopWg.Done()
+		m.processLoop()
+	}()
+
+	m.logger.InfoContext(m.ctx, "processor started")
+	return nil
+}
+
+// StartScheduler starts running the scheduled tasks
+func (m *Manager) StartScheduler() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.schedulerEnabled {
+		return fmt.Errorf("scheduler is %w", ErrDisabled)
+	}
+	if m.schedulerDone != nil {
+		return &alreadyError{"scheduler", StatusRunning}
+	}
+	if err := m.validateConfig(schedulerSecrets); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(logging.With(context.Background(), "worker_id", m.workerID, "instance_id", m.instanceID))
+	done := make(chan struct{})
+	m.schedulerCancel, m.schedulerDone = cancel, done
+	go func() {
+		defer close(done)
+		m.scheduler.Start(ctx)
+	}()
+	m.setSchedulerStatus()
 
-	log.Printf("Worker %s started", m.workerID)
+	m.logger.InfoContext(ctx, "scheduler started")
 	return nil
 }
 
-func (m *Manager) Stop() error {
+// StopScheduler cancels scheduled task runs in progress and waits for them to return
+func (m *Manager) StopScheduler() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if !m.isRunning {
-		return fmt.Errorf("worker is not running")
+	if m.schedulerDone == nil {
+		if !m.schedulerEnabled {
+			return fmt.Errorf("scheduler is %w", ErrDisabled)
+		}
+		return &alreadyError{"scheduler", StatusStopped}
+	}
+	// Scheduled tasks never take m.mu, so waiting under it is safe and keeps
+	// a concurrent StartScheduler from overlapping the old run
+	m.schedulerCancel()
+	<-m.schedulerDone
+	m.schedulerCancel, m.schedulerDone = nil, nil
+	m.setSchedulerStatus()
+
+	m.logger.Info("scheduler stopped")
+	return nil
+}
+
+// PauseScheduledTask stops one scheduled task from running until it is resumed
+func (m *Manager) PauseScheduledTask(name string) error {
+	if err := m.scheduler.PauseTask(name); err != nil {
+		return err
+	}
+	m.logger.Info("scheduled task paused", "scheduled_task", name)
+	return nil
+}
+
+func (m *Manager) ResumeScheduledTask(name string) error {
+	if err := m.scheduler.ResumeTask(name); err != nil {
+		return err
+	}
+	m.logger.Info("scheduled task resumed", "scheduled_task", name)
+	return nil
+}
+
+// Pause stops claiming new tasks; tasks in flight carry on to completion
+func (m *Manager) Pause() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status != StatusRunning {
+		return fmt.Errorf("processor is %s", m.status)
+	}
+	m.setStatus(StatusPaused)
+	m.logger.InfoContext(m.ctx, "processor paused", "in_flight", m.inFlight())
+	return nil
+}
+
+// Resume starts claiming tasks again after Pause
+func (m *Manager) Resume() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status != StatusPaused {
+		return fmt.Errorf("processor is %s", m.status)
+	}
+	m.setStatus(StatusRunning)
+	m.logger.InfoContext(m.ctx, "processor resumed")
+	return nil
+}
+
+// Drain stops claiming, then stops the processor once the tasks in flight
+// finish. Tasks still running after timeout are cancelled and requeued.
+// It returns at once; progress shows in the metrics.
+func (m *Manager) Drain(timeout time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status != StatusRunning && m.status != StatusPaused {
+		return fmt.Errorf("processor is %s", m.status)
+	}
+
+	now := time.Now()
+	deadline := now.Add(timeout)
+	m.metrics.Lock()
+	m.metrics.DrainStartedAt = &now
+	m.metrics.DrainDeadline = &deadline
+	m.metrics.Unlock()
+	metrics.WorkerDrainDeadline.WithLabelValues(m.workerID).Set(float64(deadline.Unix()))
+	m.setStatus(StatusDraining)
+	m.logger.InfoContext(m.ctx, "processor draining", "in_flight", m.inFlight(), "deadline", deadline)
+
+	m.cancel()
+	go m.drain(m.cancelTasks, m.done, timeout)
+	return nil
+}
+
+func (m *Manager) drain(cancelTasks context.CancelFunc, done chan struct{}, timeout time.Duration) {
+	finished := make(chan struct{})
+	go func() {
+		m.wait()
+		close(finished)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-finished:
+	case <-timer.C:
+		m.logger.WarnContext(m.ctx, "drain deadline reached, cancelling remaining tasks", "in_flight", m.inFlight())
+		cancelTasks()
+		<-finished
 	}
+	m.stopped(done)
 
-	log.Printf("Worker %s stopping - waiting for tasks to complete", m.workerID)
+	// A start requested during the drain can go ahead now
+	select {
+	case m.desiredChanged <- struct{}{}:
+	default:
+	}
+}
 
-	// Cancel context and wait for all processing to complete
+// StopProcessor cancels the loop and every task in flight at once. Cancelled
+// tasks are put back to pending. Stopping a draining processor cuts the drain short.
+func (m *Manager) StopProcessor() error {
+	m.mu.Lock()
+	if m.status == StatusStopped || m.status == StatusDisabled {
+		status := m.status
+		m.mu.Unlock()
+		if status == StatusDisabled {
+			return fmt.Errorf("processor is %w", ErrDisabled)
+		}
+		return &alreadyError{"processor", StatusStopped}
+	}
+	m.logger.InfoContext(m.ctx, "processor stopping, cancelling tasks in flight", "in_flight", m.inFlight())
 	m.cancel()
+	m.cancelTasks()
+	done := m.done
+	// Tasks take m.mu to check the status, so wait without holding it
+	m.mu.Unlock()
+
+	m.wait()
+	m.stopped(done)
+	return nil
+}
+
+// Shutdown stops the scheduler, drains the processor and waits until it has
+// stopped, for use when the process exits
+func (m *Manager) Shutdown(timeout time.Duration) {
+	// An error only means the scheduler was not running
+	m.StopScheduler()
+
+	m.mu.Lock()
+	status, done := m.status, m.done
+	m.mu.Unlock()
+
+	switch status {
+	case StatusStopped, StatusDisabled:
+		return
+	case StatusRunning, StatusPaused:
+		if err := m.Drain(timeout); err != nil {
+			// Stopped or started draining in the meantime
+			m.logger.Info("processor drain skipped", "reason", err)
+		}
+	}
+	<-done
+}
+
+// Status reports the processor, the scheduler and each scheduled task
+func (m *Manager) Status() Status {
+	m.mu.Lock()
+	processorStatus := m.status
+	schedulerStatus := m.schedulerStatus()
+	m.mu.Unlock()
+
+	m.metrics.Lock()
+	desired := m.settings.Current()
+	processor := ProcessorStatus{
+		Status:        processorStatus,
+		Desired:       desired.Processor,
+		InFlight:      m.metrics.CurrentTasks,
+		DrainDeadline: m.metrics.DrainDeadline,
+	}
+	m.metrics.Unlock()
+
+	paused := desired.PausedScheduledTasks
+	if paused == nil {
+		paused = []string{}
+	}
+	applyError, _ := m.applyError.Load().(string)
+
+	return Status{
+		Processor: processor,
+		Scheduler: SchedulerStatus{
+			Status:      schedulerStatus,
+			Desired:     desired.Scheduler,
+			PausedTasks: paused,
+			Tasks:       m.scheduler.Tasks(),
+		},
+		ApplyError: applyError,
+	}
+}
+
+// processorStatus returns running, paused, draining, stopped or disabled
+func (m *Manager) processorStatus() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// schedulerStatus returns running, stopped or disabled. Callers hold m.mu.
+func (m *Manager) schedulerStatus() string {
+	switch {
+	case !m.schedulerEnabled:
+		return StatusDisabled
+	case m.schedulerDone != nil:
+		return StatusRunning
+	default:
+		return StatusStopped
+	}
+}
+
+// wait blocks until the loop has exited and the tasks it claimed have finished
+func (m *Manager) wait() {
+	m.loopWg.Wait()
 	m.processingWg.Wait()
+}
 
-	// Update status
-	m.isRunning = false
+// stopped marks the run that owns done as stopped; a drain and a hard stop may
+// both get here for the same run
+func (m *Manager) stopped(done chan struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.done != done || m.status == StatusStopped {
+		return
+	}
 	m.metrics.Lock()
-	m.metrics.WorkerStatus = "stopped"
+	m.metrics.DrainStartedAt = nil
+	m.metrics.DrainDeadline = nil
 	m.metrics.Unlock()
+	metrics.WorkerDrainDeadline.WithLabelValues(m.workerID).Set(0)
+	m.setStatus(StatusStopped)
+	close(done)
+	m.logger.InfoContext(m.ctx, "processor stopped")
+}
 
-	log.Printf("Worker %s stopped", m.workerID)
-	return nil
+// setStatus records the processor's status in the manager and its metrics.
+// Callers hold m.mu, except while the manager is being built.
+func (m *Manager) setStatus(status string) {
+	m.status = status
+	m.metrics.Lock()
+	m.metrics.WorkerStatus = status
+	m.metrics.Unlock()
+	for _, s := range statuses {
+		value := 0.0
+		if s == status {
+			value = 1
+		}
+		metrics.WorkerStatus.WithLabelValues(m.workerID, s).Set(value)
+	}
 }
 
-func (m *Manager) GetMetrics() *Metrics {
+// setSchedulerStatus copies the scheduler's status to the metrics. Callers hold
+// m.mu, except while the manager is being built.
+func (m *Manager) setSchedulerStatus() {
+	status := m.schedulerStatus()
+	m.metrics.Lock()
+	m.metrics.SchedulerStatus = status
+	m.metrics.Unlock()
+}
+
+func (m *Manager) inFlight() int {
 	m.metrics.Lock()
 	defer m.metrics.Unlock()
-	return m.metrics
+	return m.metrics.CurrentTasks
 }
 
-// Run starts the manager and blocks until context is cancelled
-func (m *Manager) Run(ctx context.Context) error {
-	// Start the worker
-	if err := m.Start(); err != nil {
-		return fmt.Errorf("start worker: %w", err)
+func (m *Manager) GetMetrics() *Metrics {
+	m.metrics.Lock()
+	defer m.metrics.Unlock()
+	// A copy, so callers can read it after the lock is released. The drain
+	// times are replaced rather than modified, so sharing them is safe.
+	return &Metrics{
+		TasksProcessed:  m.metrics.TasksProcessed,
+		TasksSucceeded:  m.metrics.TasksSucceeded,
+		TasksFailed:     m.metrics.TasksFailed,
+		TasksUnchanged:  m.metrics.TasksUnchanged,
+		TasksRequeued:   m.metrics.TasksRequeued,
+		WorkerStatus:    m.metrics.WorkerStatus,
+		SchedulerStatus: m.metrics.SchedulerStatus,
+		LastStartTime:   m.metrics.LastStartTime,
+		LastErrorTime:   m.metrics.LastErrorTime,
+		LastError:       m.metrics.LastError,
+		CurrentTasks:    m.metrics.CurrentTasks,
+		DrainStartedAt:  m.metrics.DrainStartedAt,
+		DrainDeadline:   m.metrics.DrainDeadline,
 	}
+}
 
-	// Wait for context cancellation
-	<-ctx.Done()
+// Muninn is the shared Muninn client, so API lookups reuse the worker's token and rate limit
+func (m *Manager) Muninn() *muninn.Client {
+	return m.muninn
+}
 
-	// Stop the worker
-	if err := m.Stop(); err != nil {
-		return fmt.Errorf("stop worker: %w", err)
-	}
+// Run keeps the enabled components in the desired state from the settings until
+// ctx is cancelled, then shuts them down, cancelling tasks still running after
+// drainTimeout. A component that fails to start is retried; see reconcileInterval.
+func (m *Manager) Run(ctx context.Context, drainTimeout time.Duration) {
+	m.reconcileLoop(ctx)
 
-	return nil
+	// Keep API requests from restarting the components during shutdown
+	m.reconcileMu.Lock()
+	m.reconcileDone = true
+	m.reconcileMu.Unlock()
+	m.Shutdown(drainTimeout)
 }
-func (m *Manager) validateConfig() error {
-	required := []struct {
-			name  string
-			value string
-	}{
-		{"NOSCOPE_ENRICH_URL", os.Getenv("NOSCOPE_ENRICH_URL")},
-		{"NOSCOPE_KEY", os.Getenv("NOSCOPE_KEY")},
-		{"MUNINN_UPSERT_OBJTYPE_URL", os.Getenv("MUNINN_UPSERT_OBJTYPE_URL")},
-		{"MUNINN_JWT", os.Getenv("MUNINN_JWT")},
-		{"MUNINN_NOSCOPE_OBJTYPE_ID", os.Getenv("MUNINN_NOSCOPE_OBJTYPE_ID")},
-		{"MUNINN_SCAN_OBJECTS_URL", os.Getenv("MUNINN_SCAN_OBJECTS_URL")},
-	}
-
-	var missing []string
-	for _, req := range required {
-		if req.value == "" {
-			missing = append(missing, req.name)
-		}
+// Secrets each component needs besides the Muninn client's credentials
+var (
+	processorSecrets = []string{
+		secrets.NoscopeEnrichURL,
+		secrets.NoscopeKey,
+		secrets.MuninnUpsertObjTypeURL,
+		secrets.MuninnNoscopeObjTypeID,
+		secrets.MuninnTagsObjURL,
+		secrets.MuninnUntagsObjURL,
+		secrets.MuninnObjectURL,
+	}
+	schedulerSecrets = []string{
+		secrets.MuninnScanObjectsURL,
 	}
+)
+
+func (m *Manager) validateConfig(required []string) error {
+	missing := m.secrets.Missing(required...)
+	missing = append(missing, m.muninn.Missing()...)
 
 	if len(missing) > 0 {
-		return fmt.Errorf("missing required environment variables: %v", missing)
+		return fmt.Errorf("missing required secrets: %v", missing)
 	}
 
 	return nil
 }
 
-func (m *Manager) logError(errMsg string) {
+// logError logs err with the correlation fields carried by ctx and records it as the worker's last error
+func (m *Manager) logError(ctx context.Context, msg string, err error) {
 	m.metrics.Lock()
 	m.metrics.LastErrorTime = time.Now()
-	m.metrics.LastError = errMsg
+	m.metrics.LastError = fmt.Sprintf("%s: %v", msg, err)
 	m.metrics.Unlock()
-	log.Printf("Worker %s error: %s", m.workerID, errMsg)
+	m.logger.ErrorContext(ctx, msg, "error", err)
 }
\ No newline at end of file
@@ -0,0 +1,237 @@
+package worker
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// FieldType is the Muninn-side type a Noscope field is coerced into
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldURL    FieldType = "url"
+	FieldNumber FieldType = "number"
+	FieldBool   FieldType = "bool"
+)
+
+// FieldMapping describes how one Noscope response field lands in Muninn type_values
+type FieldMapping struct {
+	Source   string      `json:"source"`
+	Target   string      `json:"target"`
+	Type     FieldType   `json:"type"`
+	Required bool        `json:"required"`
+	Default  interface{} `json:"default,omitempty"`
+}
+
+// Mapping is the declarative translation applied between NoscopeResponse and MuninnUpsertRequest.
+// Bump Version whenever Fields change so tasks record which mapping produced their type_values.
+type Mapping struct {
+	Version     string         `json:"version"`
+	Fields      []FieldMapping `json:"fields"`
+	DropUnknown bool           `json:"drop_unknown"`
+}
+
+// DefaultMapping mirrors the data_models requested from Noscope
+var DefaultMapping = &Mapping{
+	Version:     "noscope-v1",
+	DropUnknown: true,
+	Fields: []FieldMapping{
+		{Source: "name", Target: "name", Type: FieldString, Required: true},
+		{Source: "github", Target: "github", Type: FieldURL},
+		{Source: "labels", Target: "labels", Type: FieldString},
+		{Source: "caption", Target: "caption", Type: FieldString},
+		{Source: "summary", Target: "summary", Type: FieldString},
+		{Source: "linkedin", Target: "linkedin", Type: FieldURL},
+		{Source: "framework", Target: "framework", Type: FieldString},
+		{Source: "blockchain", Target: "blockchain", Type: FieldString},
+		{Source: "product_category", Target: "product_category", Type: FieldString},
+		{Source: "professional_dev", Target: "professional_dev", Type: FieldString},
+		{Source: "organisation", Target: "organisation", Type: FieldString},
+		{Source: "organisation_url", Target: "organisation_url", Type: FieldURL},
+	},
+}
+
+// MappingError lists every field that failed validation, so one failed task explains all problems at once
+type MappingError struct {
+	Version  string
+	Problems []string
+}
+
+func (e *MappingError) Error() string {
+	return fmt.Sprintf("mapping %s: %s", e.Version, strings.Join(e.Problems, "; "))
+}
+
+func (m *Manager) mappingVersion() sql.NullString {
+	return sql.NullString{String: m.mapping.Version, Valid: true}
+}
+
+// Sources returns the Noscope data_models this mapping reads
+func (mp *Mapping) Sources() []string {
+	sources := make([]string, 0, len(mp.Fields))
+	for _, f := range mp.Fields {
+		sources = append(sources, f.Source)
+	}
+	return sources
+}
+
+// CheckSources reports an error when models, the data_models requested from
+// Noscope, leave out a field the mapping requires
+func (mp *Mapping) CheckSources(models []string) error {
+	requested := make(map[string]bool, len(models))
+	for _, m := range models {
+		requested[m] = true
+	}
+	var missing []string
+	for _, f := range mp.Fields {
+		if f.Required && f.Default == nil && !requested[f.Source] {
+			missing = append(missing, f.Source)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("data models must include %s, required by mapping %s", strings.Join(missing, ", "), mp.Version)
+	}
+	return nil
+}
+
+// Apply turns a raw Noscope response into Muninn type_values
+func (mp *Mapping) Apply(noscopeResp json.RawMessage) (json.RawMessage, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(noscopeResp))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, &MappingError{Version: mp.Version, Problems: []string{fmt.Sprintf("response is not a JSON object: %v", err)}}
+	}
+
+	typeValues := make(map[string]interface{}, len(mp.Fields))
+	if !mp.DropUnknown {
+		known := make(map[string]bool, len(mp.Fields))
+		for _, f := range mp.Fields {
+			known[f.Source] = true
+		}
+		for key, value := range raw {
+			if !known[key] {
+				typeValues[key] = value
+			}
+		}
+	}
+
+	var problems []string
+	for _, f := range mp.Fields {
+		value, present := raw[f.Source]
+		if present && !isEmptyValue(value) {
+			coerced, err := coerceField(f.Type, value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", f.Source, err))
+				continue
+			}
+			typeValues[f.Target] = coerced
+			continue
+		}
+		if f.Default != nil {
+			typeValues[f.Target] = f.Default
+			continue
+		}
+		if f.Required {
+			problems = append(problems, fmt.Sprintf("%s: required field missing", f.Source))
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, &MappingError{Version: mp.Version, Problems: problems}
+	}
+
+	out, err := json.Marshal(typeValues)
+	if err != nil {
+		return nil, fmt.Errorf("marshal type values: %w", err)
+	}
+	return out, nil
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func coerceField(fieldType FieldType, value interface{}) (interface{}, error) {
+	switch fieldType {
+	case FieldString:
+		return coerceString(value)
+	case FieldURL:
+		s, err := coerceString(value)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(s, "://") {
+			s = "https://" + s
+		}
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid url %q", s)
+		}
+		return u.String(), nil
+	case FieldNumber:
+		switch v := value.(type) {
+		case json.Number:
+			return v.Float64()
+		case string:
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot coerce %q to number", v)
+			}
+			return f, nil
+		}
+	case FieldBool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("cannot coerce %q to bool", v)
+			}
+			return b, nil
+		case json.Number:
+			return v.String() != "0", nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown field type %q", fieldType)
+	}
+	return nil, fmt.Errorf("cannot coerce %T to %s", value, fieldType)
+}
+
+func coerceString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v), nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := coerceString(item)
+			if err != nil {
+				return "", err
+			}
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", "), nil
+	}
+	return "", fmt.Errorf("cannot coerce %T to string", value)
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMappingApply(t *testing.T) {
+	mapping := &Mapping{
+		Version:     "test-v1",
+		DropUnknown: true,
+		Fields: []FieldMapping{
+			{Source: "name", Target: "name", Type: FieldString, Required: true},
+			{Source: "site", Target: "website", Type: FieldURL},
+			{Source: "employees", Target: "employees", Type: FieldNumber},
+			{Source: "active", Target: "active", Type: FieldBool},
+			{Source: "labels", Target: "labels", Type: FieldString},
+			{Source: "region", Target: "region", Type: FieldString, Required: true, Default: "global"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		mapping      *Mapping
+		in           string
+		want         map[string]interface{}
+		wantProblems []string
+	}{
+		{
+			name: "coerces every type",
+			in:   `{"name":" Acme ","site":"acme.io","employees":"12","active":"true","labels":["a"," ","b"],"region":"eu"}`,
+			want: map[string]interface{}{
+				"name":      "Acme",
+				"website":   "https://acme.io",
+				"employees": 12.0,
+				"active":    true,
+				"labels":    "a, b",
+				"region":    "eu",
+			},
+		},
+		{
+			name: "numbers and bools from JSON values",
+			in:   `{"name":42,"employees":7.5,"active":0}`,
+			want: map[string]interface{}{
+				"name":      "42",
+				"employees": 7.5,
+				"active":    false,
+				"region":    "global",
+			},
+		},
+		{
+			name: "empty values fall back to the default",
+			in:   `{"name":"Acme","region":"  ","labels":[]}`,
+			want: map[string]interface{}{"name": "Acme", "region": "global"},
+		},
+		{
+			name: "unknown fields dropped",
+			in:   `{"name":"Acme","extra":"x"}`,
+			want: map[string]interface{}{"name": "Acme", "region": "global"},
+		},
+		{
+			name: "unknown fields kept",
+			mapping: &Mapping{
+				Version: "test-v1",
+				Fields:  []FieldMapping{{Source: "name", Target: "title", Type: FieldString}},
+			},
+			in:   `{"name":"Acme","extra":"x"}`,
+			want: map[string]interface{}{"title": "Acme", "extra": "x"},
+		},
+		{
+			name: "every problem reported",
+			in:   `{"site":"http://","employees":"many","active":"maybe","labels":{"a":1}}`,
+			wantProblems: []string{
+				"name: required field missing",
+				`site: invalid url "http://"`,
+				`employees: cannot coerce "many" to number`,
+				`active: cannot coerce "maybe" to bool`,
+				"labels: cannot coerce map[string]interface {} to string",
+			},
+		},
+		{
+			name:         "not an object",
+			in:           `["Acme"]`,
+			wantProblems: []string{"response is not a JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp := tt.mapping
+			if mp == nil {
+				mp = mapping
+			}
+			out, err := mp.Apply(json.RawMessage(tt.in))
+			if tt.wantProblems != nil {
+				var mappingErr *MappingError
+				if !errors.As(err, &mappingErr) {
+					t.Fatalf("Apply() error = %v, want a MappingError", err)
+				}
+				if mappingErr.Version != mp.Version {
+					t.Errorf("MappingError.Version = %q, want %q", mappingErr.Version, mp.Version)
+				}
+				if !reflect.DeepEqual(mappingErr.Problems, tt.wantProblems) {
+					t.Errorf("MappingError.Problems = %q, want %q", mappingErr.Problems, tt.wantProblems)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatalf("Apply() returned invalid JSON %s: %v", out, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
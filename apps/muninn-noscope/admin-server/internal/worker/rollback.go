@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"admin-server/internal/database"
+	"admin-server/internal/logging"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// rollbackTimeout bounds a rollback. A rollback task still processing after this
+// long was interrupted, e.g. by a restart, and no longer blocks a new one.
+const rollbackTimeout = 2 * time.Minute
+
+// ErrRollbackInProgress is returned while another rollback of the same object is running
+var ErrRollbackInProgress = errors.New("a rollback of this object is already in progress")
+
+// RollbackError is a rollback that failed after its task was created
+type RollbackError struct {
+	TaskID *uuid.UUID
+	Err    error
+}
+
+func (e *RollbackError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
+
+// RollbackInput is stored as the input of a rollback task so the audit trail shows what was restored
+type RollbackInput struct {
+	RollbackToVersion int32      `json:"rollback_to_version"`
+	RollbackToTaskID  *uuid.UUID `json:"rollback_to_task_id,omitempty"`
+}
+
+// RollbackResult is the task recording the rollback and the history version it produced
+type RollbackResult struct {
+	TaskID   *uuid.UUID                 `json:"task_id"`
+	Snapshot *database.ObjectEnrichment `json:"snapshot"`
+}
+
+// Rollback re-applies an earlier enrichment snapshot to Muninn. It runs synchronously, records itself as a
+// 'rollback' task and appends the restored state as a new history version. The work is detached from ctx's
+// cancellation so a disconnecting client cannot leave the task processing; failures after the task is
+// created are returned as *RollbackError.
+func (m *Manager) Rollback(ctx context.Context, target *database.ObjectEnrichment) (*RollbackResult, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	input, err := json.Marshal(RollbackInput{
+		RollbackToVersion: target.Version,
+		RollbackToTaskID:  target.TaskID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal rollback input: %w", err)
+	}
+
+	queries := database.New(m.db)
+	now := time.Now()
+	if _, err := queries.FailInterruptedRollbacks(ctx, database.FailInterruptedRollbacksParams{
+		ObjectID:  target.ObjectID,
+		StartedAt: sql.NullTime{Time: now.Add(-rollbackTimeout), Valid: true},
+	}); err != nil {
+		return nil, fmt.Errorf("clear interrupted rollbacks: %w", err)
+	}
+	// A unique index allows one processing rollback task per object
+	row, err := queries.CreateRollbackTask(ctx, database.CreateRollbackTaskParams{
+		ObjectID:  target.ObjectID,
+		Input:     input,
+		StartedAt: sql.NullTime{Time: now, Valid: true},
+	})
+	if database.IsUniqueViolation(err) {
+		return nil, ErrRollbackInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create rollback task: %w", err)
+	}
+	task := database.UpdateTaskProcessingRow(row)
+	ctx = logging.With(ctx, "task_id", task.ID, "object_id", task.ObjectID)
+	m.logger.InfoContext(ctx, "rolling back object", "version", target.Version)
+
+	if err := m.callMuninnUpsert(ctx, &task, target.TypeValues); err != nil {
+		err = fmt.Errorf("Muninn upsert failed: %w", err)
+		m.finishRollbackTask(ctx, &task, target, "failed", err)
+		return nil, &RollbackError{TaskID: task.ID, Err: err}
+	}
+
+	if err := m.syncObjectTags(ctx, *task.ObjectID, target.Tags); err != nil {
+		err = fmt.Errorf("Muninn tag failed: %w", err)
+		m.finishRollbackTask(ctx, &task, target, "failed", err)
+		return nil, &RollbackError{TaskID: task.ID, Err: err}
+	}
+
+	snapshot, err := m.recordEnrichment(ctx, task.ObjectID, task.ID, target.TypeValues, target.Tags, target.MappingVersion)
+	if err != nil {
+		m.finishRollbackTask(ctx, &task, target, "failed", err)
+		return nil, &RollbackError{TaskID: task.ID, Err: err}
+	}
+	if err := m.saveContentHash(ctx, task.ObjectID, snapshot.ContentHash); err != nil {
+		m.logError(ctx, "save content hash failed", err)
+	}
+
+	m.finishRollbackTask(ctx, &task, target, "completed", nil)
+	return &RollbackResult{TaskID: task.ID, Snapshot: snapshot}, nil
+}
+
+// finishRollbackTask records the outcome. It runs even if the rollback ran out of
+// time, so the task never stays processing.
+func (m *Manager) finishRollbackTask(ctx context.Context, task *database.UpdateTaskProcessingRow, target *database.ObjectEnrichment, status string, taskErr error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	now := time.Now()
+	var errMsg sql.NullString
+	if taskErr != nil {
+		errMsg = sql.NullString{String: taskErr.Error(), Valid: true}
+	}
+
+	queries := database.New(m.db)
+	if err := queries.UpdateTaskStatus(ctx, database.UpdateTaskStatusParams{
+		Status:         status,
+		Output:         pqtype.NullRawMessage{RawMessage: target.TypeValues, Valid: true},
+		Error:          errMsg,
+		CompletedAt:    sql.NullTime{Time: now, Valid: true},
+		MappingVersion: target.MappingVersion,
+		ID:             task.ID,
+	}); err != nil {
+		m.logError(ctx, "update rollback task status failed", err)
+		return
+	}
+
+	if status == "completed" {
+		queries.UpdateObjectLastSyncedAt(ctx, database.UpdateObjectLastSyncedAtParams{
+			ID:           task.ObjectID,
+			LastSyncedAt: sql.NullTime{Time: now, Valid: true},
+		})
+	}
+}
@@ -8,12 +8,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
 	"time"
 
 	"admin-server/internal/database"
+	"admin-server/internal/metrics"
+	"admin-server/internal/muninn"
+	"admin-server/internal/secrets"
+	"admin-server/internal/settings"
 
 	"github.com/google/uuid"
 )
@@ -29,15 +32,20 @@ type ScanObjectsResponse struct {
 }
 
 type ScanTask struct {
-	client  *http.Client
+	muninn  *muninn.Client
 	queries *database.Queries
-	logger   *log.Logger
+	secrets *secrets.Store
+	// settings supplies stale_days
+	settings *settings.Store
+	logger   *slog.Logger
 }
 
-func NewScanTask(queries *database.Queries, client *http.Client, logger *log.Logger) *ScanTask {
+func NewScanTask(queries *database.Queries, muninnClient *muninn.Client, secretStore *secrets.Store, settingsStore *settings.Store, logger *slog.Logger) *ScanTask {
 	return &ScanTask{
-		client:  client,
+		muninn:  muninnClient,
 		queries: queries,
+		secrets: secretStore,
+		settings: settingsStore,
 		logger:  logger,
 	}
 }
@@ -75,6 +83,7 @@ func (t *ScanTask) scanNewObjects(ctx context.Context) error {
 		return err
 	}
 	// t.logger.Println("scanNewObjects, resp.Objects.length: ",len(resp.Objects))
+	t.logger.InfoContext(ctx, "scanned new objects", "count", len(resp.Objects), "created_after", lastScan.Time)
 	// Create tasks for each object
 	for _, obj := range resp.Objects {
 		// check if the object existed
@@ -87,6 +96,7 @@ func (t *ScanTask) scanNewObjects(ctx context.Context) error {
 		if _,err := t.queries.CreateTask(ctx, database.CreateTaskParams{
 			ObjectID: &obj.ID,
 			Input:    obj.ContactData,
+			Source:   "scan",
 		}); err != nil {
 			return fmt.Errorf("create task for object %s: %w", obj.ID, err)
 		}
@@ -107,8 +117,8 @@ func (t *ScanTask) scanNewObjects(ctx context.Context) error {
 }
 
 func (t *ScanTask) scanStaleObjects(ctx context.Context) error {
-	// Get objects not synced in 60 days
-	staleTime := time.Now().AddDate(0, 0, -60)
+	// Get objects not synced within stale_days
+	staleTime := time.Now().Add(-t.settings.Current().StaleAfter())
 	staleObjects, err := t.queries.GetStaleObjects(ctx, sql.NullTime{
 		Time: staleTime, Valid: true,
 	})
@@ -138,11 +148,13 @@ func (t *ScanTask) scanStaleObjects(ctx context.Context) error {
 		return err
 	}
 
+	t.logger.InfoContext(ctx, "scanned stale objects", "count", len(resp.Objects))
 	// Create tasks for each object
 	for _, obj := range resp.Objects {
 		if _,err := t.queries.CreateTask(ctx, database.CreateTaskParams{
 			ObjectID: &obj.ID,
 			Input:    obj.ContactData,
+			Source:   "stale",
 		}); err != nil {
 			return fmt.Errorf("create task for object %s: %w", obj.ID, err)
 		}
@@ -158,15 +170,14 @@ func (t *ScanTask) callMuninnScanAPI(ctx context.Context, body interface{}) (*Sc
 	}
 	// t.logger.Println("CallMuninnScanAPI: ",os.Getenv("MUNINN_SCAN_OBJECTS_URL"))
 	// t.logger.Println(body);
-	req, err := http.NewRequestWithContext(ctx, "POST", os.Getenv("MUNINN_SCAN_OBJECTS_URL"), bytes.NewReader(reqBody))
+	req, err := http.NewRequestWithContext(metrics.WithUpstream(ctx, "muninn_scan"), "POST", t.secrets.Get(secrets.MuninnScanObjectsURL), bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", os.Getenv("MUNINN_JWT")))
 
-	resp, err := t.client.Do(req)
+	resp, err := t.muninn.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("execute request: %w", err)
 	}
@@ -190,7 +201,7 @@ func (t *ScanTask) callMuninnScanAPI(ctx context.Context, body interface{}) (*Sc
 }
 
 func (t *ScanTask) Handle(ctx context.Context) error {
-	t.logger.Println("Running scan task")
+	t.logger.InfoContext(ctx, "running scan task")
 	if err := t.scanNewObjects(ctx); err != nil {
 		return fmt.Errorf("scan new objects: %w", err)
 	}
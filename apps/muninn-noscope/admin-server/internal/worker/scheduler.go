@@ -2,17 +2,24 @@
 package worker
 
 import (
+	"admin-server/internal/logging"
+	"admin-server/internal/metrics"
 	"admin-server/internal/worker/schedule_task"
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sort"
 	"sync"
 	"time"
 )
 
+// ErrUnknownTask is returned for a scheduled task name that was never added
+var ErrUnknownTask = errors.New("unknown scheduled task")
+
 type Scheduler struct {
 	tasks     map[string]*ScheduledTask
-	logger    *log.Logger
+	logger    *slog.Logger
 	wg        sync.WaitGroup      // Add WaitGroup for tracking running tasks
 	mu        sync.RWMutex        // Add mutex for tasks map
 	isRunning bool
@@ -21,10 +28,32 @@ type Scheduler struct {
 type ScheduledTask struct {
 	Handler  schedule_task.TaskHandler
 	Interval time.Duration
+	// LastRun is when the last successful run started; the next run is due Interval after it
 	LastRun  time.Time
+	// Paused tasks are skipped until resumed
+	Paused   bool
+	// Running is set while a run is in progress, so a slow run is not started twice
+	Running      bool
+	LastAttempt  time.Time
+	LastDuration time.Duration
+	LastError    string
+}
+
+// ScheduledTaskStatus describes one task for GET /api/worker/status
+type ScheduledTaskStatus struct {
+	Name           string     `json:"name"`
+	Interval       string     `json:"interval"`
+	Paused         bool       `json:"paused"`
+	Running        bool       `json:"running"`
+	LastRun        *time.Time `json:"last_run,omitempty"`
+	LastAttempt    *time.Time `json:"last_attempt,omitempty"`
+	LastDurationMs int64      `json:"last_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	// NextRun is when the task is next due; it may be in the past while the scheduler is stopped
+	NextRun        *time.Time `json:"next_run,omitempty"`
 }
 
-func NewScheduler(logger *log.Logger) *Scheduler {
+func NewScheduler(logger *slog.Logger) *Scheduler {
 	return &Scheduler{
 		tasks:     make(map[string]*ScheduledTask),
 		logger:    logger,
@@ -43,6 +72,74 @@ func (s *Scheduler) AddTask(name string, handler schedule_task.TaskHandler, inte
 	}
 }
 
+// SetInterval changes how often a task runs; the next run is due interval after its last one
+func (s *Scheduler) SetInterval(name string, interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, exists := s.tasks[name]; exists {
+		t.Interval = interval
+	}
+}
+
+// PauseTask stops a task from being started; a run in progress carries on
+func (s *Scheduler) PauseTask(name string) error {
+	return s.setPaused(name, true)
+}
+
+// ResumeTask lets a paused task run again, at once if it is overdue
+func (s *Scheduler) ResumeTask(name string) error {
+	return s.setPaused(name, false)
+}
+
+func (s *Scheduler) setPaused(name string, paused bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, exists := s.tasks[name]
+	if !exists {
+		return fmt.Errorf("%w %q", ErrUnknownTask, name)
+	}
+	t.Paused = paused
+	return nil
+}
+
+// Tasks returns the state of every task, by name
+func (s *Scheduler) Tasks() []ScheduledTaskStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	optionalTime := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+	tasks := make([]ScheduledTaskStatus, 0, len(s.tasks))
+	for name, t := range s.tasks {
+		status := ScheduledTaskStatus{
+			Name:           name,
+			Interval:       t.Interval.String(),
+			Paused:         t.Paused,
+			Running:        t.Running,
+			LastRun:        optionalTime(t.LastRun),
+			LastAttempt:    optionalTime(t.LastAttempt),
+			LastDurationMs: t.LastDuration.Milliseconds(),
+			LastError:      t.LastError,
+		}
+		if !t.Paused {
+			next := t.LastRun.Add(t.Interval)
+			if t.LastRun.IsZero() {
+				next = time.Now()
+			}
+			status.NextRun = &next
+		}
+		tasks = append(tasks, status)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Name < tasks[j].Name
+	})
+	return tasks
+}
+
 func (s *Scheduler) Start(ctx context.Context) error {
 	s.mu.Lock()
 	if s.isRunning {
@@ -58,12 +155,12 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	for {
 		select {
 		case <-ctx.Done():
-			s.logger.Println("Scheduler received shutdown signal, waiting for tasks to complete...")
+			s.logger.InfoContext(ctx, "scheduler received shutdown signal, waiting for tasks to complete")
 			s.wg.Wait()
 			s.mu.Lock()
 			s.isRunning = false
 			s.mu.Unlock()
-			s.logger.Println("Scheduler shutdown complete")
+			s.logger.InfoContext(ctx, "scheduler shutdown complete")
 			return nil
 		case <-ticker.C:
 			s.runDueTasks(ctx)
@@ -74,41 +171,63 @@ func (s *Scheduler) Start(ctx context.Context) error {
 func (s *Scheduler) runDueTasks(ctx context.Context) {
 	now := time.Now()
 	
-	// Use RLock for reading the tasks map
-	s.mu.RLock()
+	// Mark due tasks as running under the lock so the next tick skips them
+	s.mu.Lock()
 	tasksCopy := make(map[string]*ScheduledTask, len(s.tasks))
 	for name, task := range s.tasks {
-		tasksCopy[name] = task
+		if task.Paused || task.Running || now.Sub(task.LastRun) < task.Interval {
+			continue
+		}
+		task.Running = true
+		// Copy the task so SetInterval and LastRun updates do not race with this run
+		copied := *task
+		tasksCopy[name] = &copied
 	}
-	s.mu.RUnlock()
+	s.mu.Unlock()
 
 	for name, task := range tasksCopy {
-		if now.Sub(task.LastRun) >= task.Interval {
-			s.wg.Add(1)
-			go func(name string, task *ScheduledTask) {
-				defer s.wg.Done()
-				
-				// Create a timeout context for the task
-				taskCtx, cancel := context.WithTimeout(ctx, task.Interval/2)
-				defer cancel()
-
-				s.logger.Printf("Running scheduled task: %s\n", name)
-				
-				if err := task.Handler.Handle(taskCtx); err != nil {
-					s.logger.Printf("Error running task %s: %v\n", name, err)
-					return
-				}
+		s.wg.Add(1)
+		go func(name string, task *ScheduledTask) {
+			defer s.wg.Done()
+			
+			// Create a timeout context for the task
+			taskCtx, cancel := context.WithTimeout(logging.With(ctx, "scheduled_task", name), task.Interval/2)
+			defer cancel()
+
+			s.logger.InfoContext(taskCtx, "running scheduled task")
+			
+			start := time.Now()
+			err := task.Handler.Handle(taskCtx)
+			outcome := "success"
+			if err != nil {
+				outcome = "error"
+			}
+			metrics.SchedulerRuns.WithLabelValues(name, outcome).Inc()
+			metrics.SchedulerRunDuration.WithLabelValues(name, outcome).Observe(time.Since(start).Seconds())
 
-				// Update LastRun time under lock
-				s.mu.Lock()
-				if t, exists := s.tasks[name]; exists {
+			// Record the outcome under lock; LastRun only moves on success so a
+			// failed run is retried on the next tick
+			s.mu.Lock()
+			if t, exists := s.tasks[name]; exists {
+				t.Running = false
+				t.LastAttempt = now
+				t.LastDuration = time.Since(start)
+				t.LastError = ""
+				if err != nil {
+					t.LastError = err.Error()
+				} else {
 					t.LastRun = now
 				}
-				s.mu.Unlock()
-				
-				s.logger.Printf("Completed scheduled task: %s\n", name)
-			}(name, task)
-		}
+			}
+			s.mu.Unlock()
+
+			if err != nil {
+				s.logger.ErrorContext(taskCtx, "scheduled task failed", "error", err)
+				return
+			}
+			
+			s.logger.InfoContext(taskCtx, "completed scheduled task", "duration_ms", time.Since(start).Milliseconds())
+		}(name, task)
 	}
 }
 
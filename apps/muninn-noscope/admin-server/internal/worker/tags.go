@@ -0,0 +1,163 @@
+package worker
+
+import (
+	"admin-server/internal/database"
+	"admin-server/internal/secrets"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RawTags returns the unnormalised tag fragments from every tag-bearing Noscope field
+func (n NoscopeResponse) RawTags() []string {
+	var raw []string
+	for _, field := range []TagField{n.Labels, n.Blockchain, n.Framework, n.ProductCategory} {
+		raw = append(raw, strings.FieldsFunc(string(field), Split)...)
+	}
+	return raw
+}
+
+// NormalizeTag trims, case-folds and collapses inner whitespace so "  Solana  Dev" and "solana dev" match
+func NormalizeTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+}
+
+// TagNormalizer turns raw Noscope fragments into the tag set sent to Muninn
+type TagNormalizer struct {
+	aliases map[string]string
+	blocked map[string]bool
+	maxTags int
+}
+
+func NewTagNormalizer(aliases []database.TagAlias, blocked []database.TagBlocklist, maxTags int) *TagNormalizer {
+	n := &TagNormalizer{
+		aliases: make(map[string]string, len(aliases)),
+		blocked: make(map[string]bool, len(blocked)),
+		maxTags: maxTags,
+	}
+	for _, a := range aliases {
+		n.aliases[NormalizeTag(a.Alias)] = NormalizeTag(a.Tag)
+	}
+	for _, b := range blocked {
+		n.blocked[NormalizeTag(b.Tag)] = true
+	}
+	return n
+}
+
+// Normalize applies case-folding, aliases, the blocklist, dedupe and the max-tags cap, keeping first-seen order
+func (n *TagNormalizer) Normalize(raw []string) []string {
+	seen := make(map[string]bool, len(raw))
+	tags := make([]string, 0, len(raw))
+	for _, r := range raw {
+		tag := NormalizeTag(r)
+		if canonical, ok := n.aliases[tag]; ok {
+			tag = canonical
+		}
+		if tag == "" || n.blocked[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if n.maxTags > 0 && len(tags) >= n.maxTags {
+			break
+		}
+	}
+	return tags
+}
+
+// loadTagNormalizer reads the alias table and blocklist fresh so API edits apply to the next task
+func (m *Manager) loadTagNormalizer(ctx context.Context) (*TagNormalizer, error) {
+	queries := database.New(m.db)
+	aliases, err := queries.ListTagAliases(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tag aliases: %w", err)
+	}
+	blocked, err := queries.ListBlockedTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list blocked tags: %w", err)
+	}
+	return NewTagNormalizer(aliases, blocked, m.maxTags), nil
+}
+
+// syncObjectTags adds newly produced tags and removes ones this worker added earlier but no longer produces.
+// Only tags recorded in object_tags are ever removed, and a tag already on the object in Muninn is never
+// recorded, so tags humans add in Muninn are left alone.
+func (m *Manager) syncObjectTags(ctx context.Context, objID uuid.UUID, tags []string) error {
+	queries := database.New(m.db)
+	previous, err := queries.ListObjectTags(ctx, &objID)
+	if err != nil {
+		return fmt.Errorf("list applied tags: %w", err)
+	}
+
+	toAdd, toRemove := diffTags(previous, tags)
+
+	if len(toAdd) > 0 {
+		current, err := m.fetchMuninnTags(ctx, objID)
+		if err != nil {
+			return err
+		}
+		toAdd = withoutTags(toAdd, current)
+	}
+
+	if len(toAdd) > 0 {
+		if err := m.callMuninnTagAPI(ctx, m.secrets.Get(secrets.MuninnTagsObjURL), objID, toAdd); err != nil {
+			return err
+		}
+		for _, tag := range toAdd {
+			if err := queries.CreateObjectTag(ctx, database.CreateObjectTagParams{ObjectID: &objID, Tag: tag}); err != nil {
+				return fmt.Errorf("record applied tag %q: %w", tag, err)
+			}
+		}
+	}
+
+	if len(toRemove) > 0 {
+		if err := m.callMuninnTagAPI(ctx, m.secrets.Get(secrets.MuninnUntagsObjURL), objID, toRemove); err != nil {
+			return err
+		}
+		for _, tag := range toRemove {
+			if err := queries.DeleteObjectTag(ctx, database.DeleteObjectTagParams{ObjectID: &objID, Tag: tag}); err != nil {
+				return fmt.Errorf("forget removed tag %q: %w", tag, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// withoutTags returns the tags not in exclude, comparing normalised forms
+func withoutTags(tags, exclude []string) []string {
+	excluded := make(map[string]bool, len(exclude))
+	for _, tag := range exclude {
+		excluded[NormalizeTag(tag)] = true
+	}
+	var kept []string
+	for _, tag := range tags {
+		if !excluded[NormalizeTag(tag)] {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+// diffTags returns the tags in next but not previous, and those in previous but not next
+func diffTags(previous, next []string) (toAdd, toRemove []string) {
+	had := make(map[string]bool, len(previous))
+	for _, tag := range previous {
+		had[tag] = true
+	}
+	want := make(map[string]bool, len(next))
+	for _, tag := range next {
+		want[tag] = true
+		if !had[tag] {
+			toAdd = append(toAdd, tag)
+		}
+	}
+	for _, tag := range previous {
+		if !want[tag] {
+			toRemove = append(toRemove, tag)
+		}
+	}
+	return toAdd, toRemove
+}
@@ -0,0 +1 @@
+ALTER TABLE tasks ADD COLUMN mapping_version TEXT;
@@ -0,0 +1,15 @@
+CREATE TABLE tag_aliases (
+    alias TEXT PRIMARY KEY,
+    tag TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE tag_blocklist (
+    tag TEXT PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO tag_aliases (alias, tag) VALUES
+    ('sol', 'solana'),
+    ('eth', 'ethereum'),
+    ('btc', 'bitcoin');
@@ -0,0 +1,8 @@
+-- Tags the worker itself applied to each Muninn object, so refreshes can
+-- remove stale ones without touching tags added by humans in Muninn
+CREATE TABLE object_tags (
+    object_id UUID NOT NULL REFERENCES objects(id),
+    tag TEXT NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (object_id, tag)
+);
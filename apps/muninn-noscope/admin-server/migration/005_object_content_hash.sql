@@ -0,0 +1,7 @@
+-- Hash of the type_values and tag set last applied to Muninn, so identical
+-- refreshes can skip the Muninn calls
+ALTER TABLE objects ADD COLUMN content_hash TEXT;
+
+ALTER TABLE tasks DROP CONSTRAINT tasks_status_check;
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_check
+    CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'unchanged'));
@@ -0,0 +1,15 @@
+-- Versioned snapshots of what the worker wrote into Muninn for each object
+CREATE TABLE object_enrichments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    object_id UUID NOT NULL REFERENCES objects(id),
+    version INTEGER NOT NULL,
+    task_id UUID REFERENCES tasks(id),
+    type_values JSONB NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    content_hash TEXT NOT NULL,
+    mapping_version TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (object_id, version)
+);
+
+CREATE INDEX idx_object_enrichments_task_id ON object_enrichments(task_id);
@@ -0,0 +1,8 @@
+-- Where a task came from, so rollbacks and manual requests stay distinguishable
+-- from scanner-created refreshes in the audit trail
+ALTER TABLE tasks ADD COLUMN source TEXT NOT NULL DEFAULT 'manual'
+    CHECK (source IN ('scan', 'stale', 'manual', 'rollback'));
+
+-- At most one rollback may be in progress per object
+CREATE UNIQUE INDEX idx_tasks_one_rollback_per_object ON tasks(object_id)
+    WHERE source = 'rollback' AND status = 'processing';
@@ -0,0 +1,13 @@
+-- Keyset sort keys, matching the COALESCE expressions built in
+-- internal/database/pagination.go
+CREATE INDEX idx_tasks_created_at_key ON tasks ((COALESCE(created_at, '-infinity'::timestamptz)), id);
+CREATE INDEX idx_tasks_started_at_key ON tasks ((COALESCE(started_at, '-infinity'::timestamptz)), id);
+CREATE INDEX idx_tasks_completed_at_key ON tasks ((COALESCE(completed_at, '-infinity'::timestamptz)), id);
+CREATE INDEX idx_tasks_status_created_at ON tasks (status, created_at);
+
+-- Full-text search on error messages
+CREATE INDEX idx_tasks_error_search ON tasks USING GIN (to_tsvector('simple', COALESCE(error, '')));
+
+-- Containment lookups for JSON conditions on input/output
+CREATE INDEX idx_tasks_input ON tasks USING GIN (input jsonb_path_ops);
+CREATE INDEX idx_tasks_output ON tasks USING GIN (output jsonb_path_ops);
@@ -0,0 +1,3 @@
+-- Time-window aggregates behind GET /stats
+CREATE INDEX idx_tasks_created_at ON tasks (created_at);
+CREATE INDEX idx_tasks_completed_at ON tasks (completed_at) WHERE completed_at IS NOT NULL;
@@ -0,0 +1,7 @@
+-- Roles are ordered: viewer reads, operator also changes tasks and objects,
+-- admin also controls the worker, scheduler, configuration and users.
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'viewer'
+    CHECK (role IN ('viewer', 'operator', 'admin'));
+
+-- Users created before roles existed had full access
+UPDATE users SET role = 'admin';
@@ -0,0 +1,36 @@
+-- Who did what through the API. Rows are only ever inserted; the trigger
+-- below rejects updates and deletes so the trail cannot be rewritten.
+CREATE TABLE audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor_type TEXT NOT NULL CHECK (actor_type IN ('anonymous', 'user', 'api_key')),
+    actor_id UUID,
+    actor_name TEXT,
+    action TEXT NOT NULL,
+    target_type TEXT,
+    target_id TEXT,
+    request_id TEXT,
+    method TEXT,
+    path TEXT,
+    payload JSONB,
+    ip TEXT,
+    forwarded_for TEXT,
+    result TEXT NOT NULL CHECK (result IN ('success', 'failure', 'denied')),
+    status_code INTEGER,
+    error TEXT
+);
+
+CREATE INDEX idx_audit_events_occurred_at ON audit_events(occurred_at);
+CREATE INDEX idx_audit_events_actor ON audit_events(actor_id, occurred_at);
+CREATE INDEX idx_audit_events_action ON audit_events(action, occurred_at);
+CREATE INDEX idx_audit_events_target ON audit_events(target_type, target_id);
+
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
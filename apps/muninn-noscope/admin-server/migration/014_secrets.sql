@@ -0,0 +1,11 @@
+-- Values for the database secrets backend, sealed with AES-256-GCM under
+-- SECRETS_ENCRYPTION_KEY. The key itself never reaches the database, and
+-- version increases on every write so rotations can be told apart.
+CREATE TABLE secrets (
+    name TEXT PRIMARY KEY,
+    ciphertext BYTEA NOT NULL,
+    nonce BYTEA NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    updated_by TEXT,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
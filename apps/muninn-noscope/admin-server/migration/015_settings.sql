@@ -0,0 +1,21 @@
+-- Worker settings that can be changed at runtime. A row overrides the value from
+-- configuration; deleting it restores that value. Every change, including a
+-- reset, is kept in setting_changes with the user who made it.
+CREATE TABLE settings (
+    key TEXT PRIMARY KEY,
+    value JSONB NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE setting_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    key TEXT NOT NULL,
+    old_value JSONB,
+    new_value JSONB,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_setting_changes_key_changed_at ON setting_changes (key, changed_at DESC);
+CREATE INDEX idx_setting_changes_changed_at ON setting_changes (changed_at DESC);
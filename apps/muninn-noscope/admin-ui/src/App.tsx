@@ -21,14 +21,15 @@ import {
   useToast,
   InputGroup,
   InputRightAddon,
+  HStack,
 } from '@chakra-ui/react';
 import { TasksList } from './features/TaskList';
 import { CreateTask } from './features/CreateTask';
 import { ObjectsList } from './features/ObjectsList';
 import { WorkerManagement } from './features/WorkerManagement';
-import { login } from './api/auth';
+import { login, logout } from './api/auth';
+import { setSessionEndHandler, User } from './api/client';
 import { FaEye, FaEyeSlash } from 'react-icons/fa';
-import { SecretProvider } from './context/SecretContext';
 
 const theme = extendTheme({
   styles: {
@@ -51,16 +52,34 @@ const theme = extendTheme({
 });
 
 function App() {
-  const [isAuthenticated, setIsAuthenticated] = React.useState(false);
-  const [jwt, setJwt] = React.useState('');
-  const [secretKey, setSecretKey] = React.useState('');
+  const [user, setUser] = React.useState<User | null>(null);
+
+  React.useEffect(() => {
+    setSessionEndHandler(() => setUser(null));
+  }, []);
+
+  const signOut = async () => {
+    try {
+      await logout();
+    } finally {
+      setUser(null);
+    }
+  };
 
   return (
     <ChakraProvider theme={theme}>
       <Box minH='100vh' bg='gray.50'>
         <Container maxW='container.xl' py={8} minHeight={'600px'}>
-          {isAuthenticated && (
-            <SecretProvider value={{ jwt, secretKey, setJwt, setSecretKey }}>
+          {user && (
+            <>
+              <HStack justify='flex-end' mb={4}>
+                <Text fontSize='sm'>
+                  {user.email} ({user.role})
+                </Text>
+                <Button size='sm' onClick={signOut}>
+                  Logout
+                </Button>
+              </HStack>
               <Tabs isLazy>
                 <TabList>
                   <Tab>Tasks</Tab>
@@ -83,32 +102,18 @@ function App() {
                   </TabPanel>
                 </TabPanels>
               </Tabs>
-            </SecretProvider>
-          )}
-          {!isAuthenticated && (
-            <LoginDialog
-              setIsAuthenticated={setIsAuthenticated}
-              setJwt={setJwt}
-              setSecretKey={setSecretKey}
-            />
+            </>
           )}
+          {!user && <LoginDialog setUser={setUser} />}
         </Container>
       </Box>
     </ChakraProvider>
   );
 }
 
-const LoginDialog = ({
-  setIsAuthenticated,
-  setJwt,
-  setSecretKey,
-}: {
-  setIsAuthenticated: (isAuthenticated: boolean) => void;
-  setJwt: (jwt: string) => void;
-  setSecretKey: (secretKey: string) => void;
-}) => {
-  const [secret, setSecret] = React.useState('');
-  const [isOpen, setIsOpen] = React.useState(true);
+const LoginDialog = ({ setUser }: { setUser: (user: User) => void }) => {
+  const [email, setEmail] = React.useState('');
+  const [password, setPassword] = React.useState('');
   const [isLoading, setIsLoading] = React.useState(false);
   const toast = useToast();
   const [revealPassword, setRevealPassword] = React.useState(false);
@@ -116,11 +121,8 @@ const LoginDialog = ({
   const submit = async () => {
     try {
       setIsLoading(true);
-      const { muninn_jwt, control_secret } = await login(secret);
-      setJwt(muninn_jwt);
-      setSecretKey(control_secret);
-      setIsAuthenticated(true);
-      setIsOpen(false);
+      const { user } = await login(email, password);
+      setUser(user);
       toast({
         title: 'Success',
         description: 'Login successful',
@@ -141,17 +143,25 @@ const LoginDialog = ({
     }
   };
   return (
-    <Modal isOpen={isOpen} onClose={() => {}}>
+    <Modal isOpen onClose={() => {}}>
       <ModalHeader>Login</ModalHeader>
       <ModalContent>
         <ModalBody>
           <VStack gap={4}>
-            <Text>Enter the secret</Text>
+            <Text>Sign in with your admin account</Text>
+            <Input
+              type='email'
+              placeholder='Email'
+              value={email}
+              onChange={(e) => setEmail(e.target.value)}
+              isDisabled={isLoading}
+            />
             <InputGroup>
               <Input
                 type={revealPassword ? 'text' : 'password'}
-                value={secret}
-                onChange={(e) => setSecret(e.target.value)}
+                placeholder='Password'
+                value={password}
+                onChange={(e) => setPassword(e.target.value)}
                 isDisabled={isLoading}
                 onKeyDownCapture={(e) => {
                   e.key === 'Enter' && submit();
@@ -1,18 +1,24 @@
 import axios from 'axios';
+import { api, setTokens, TokenResponse } from './client';
+
 const API_BASE_URL = process.env.REACT_APP_API_URL;
 
-export const login = async (secret: string) => {
-  const response = await axios({
-    headers: {
-      'Content-Type': 'application/json',
-      'x-user-secret': secret,
-    },
-    method: 'post',
-    url: `${API_BASE_URL}/login`,
+export const login = async (
+  email: string,
+  password: string
+): Promise<TokenResponse> => {
+  const response = await axios.post<TokenResponse>(`${API_BASE_URL}/login`, {
+    email,
+    password,
   });
-  if (response.status === 200) {
-    return response.data;
-  } else {
-    throw new Error('Login failed');
+  setTokens(response.data);
+  return response.data;
+};
+
+export const logout = async (): Promise<void> => {
+  try {
+    await api.post('/logout');
+  } finally {
+    setTokens(null);
   }
 };
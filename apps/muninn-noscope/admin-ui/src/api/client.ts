@@ -0,0 +1,75 @@
+import axios, { AxiosError, InternalAxiosRequestConfig } from 'axios';
+
+const API_BASE_URL = process.env.REACT_APP_API_URL;
+
+export interface User {
+  id: string;
+  email: string;
+  role: 'viewer' | 'operator' | 'admin';
+  disabled: boolean;
+  created_at: string;
+  last_login_at?: string;
+}
+
+export interface TokenResponse {
+  access_token: string;
+  token_type: string;
+  expires_at: string;
+  refresh_token: string;
+  refresh_expires_at: string;
+  user: User;
+}
+
+// Tokens are kept in memory only, so a reload asks for the password again
+let accessToken = '';
+let refreshToken = '';
+let refreshing: Promise<void> | null = null;
+let onSessionEnd: () => void = () => {};
+
+export const setTokens = (tokens: TokenResponse | null) => {
+  accessToken = tokens?.access_token ?? '';
+  refreshToken = tokens?.refresh_token ?? '';
+};
+
+// setSessionEndHandler is called when the session can no longer be refreshed
+export const setSessionEndHandler = (handler: () => void) => {
+  onSessionEnd = handler;
+};
+
+export const api = axios.create({ baseURL: API_BASE_URL });
+
+api.interceptors.request.use((config) => {
+  if (accessToken) {
+    config.headers.Authorization = `Bearer ${accessToken}`;
+  }
+  return config;
+});
+
+// Access tokens are short-lived: on a 401, refresh once and retry the request
+api.interceptors.response.use(undefined, async (error: AxiosError) => {
+  const config = error.config as
+    | (InternalAxiosRequestConfig & { _retried?: boolean })
+    | undefined;
+  if (error.response?.status !== 401 || !config || config._retried || !refreshToken) {
+    throw error;
+  }
+  config._retried = true;
+  try {
+    if (!refreshing) {
+      refreshing = axios
+        .post<TokenResponse>(`${API_BASE_URL}/refresh`, {
+          refresh_token: refreshToken,
+        })
+        .then((response) => setTokens(response.data))
+        .finally(() => {
+          refreshing = null;
+        });
+    }
+    await refreshing;
+  } catch {
+    setTokens(null);
+    onSessionEnd();
+    throw error;
+  }
+  return api(config);
+});
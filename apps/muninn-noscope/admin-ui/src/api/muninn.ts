@@ -1,8 +1,6 @@
-import axios from 'axios';
 import { ListObjectsRow, ObjectDetail } from '../types';
 import { trim } from 'lodash';
-
-const MUNINN_API = process.env.REACT_APP_MUNINN_API_URL;
+import { api } from './client';
 
 // fetch
 export const crawlObject = async (object_id: string): Promise<any> => {};
@@ -14,8 +12,8 @@ export interface ListObjectResponse {
   pageSize: number;
 }
 
+// Muninn is searched through the admin server, which holds the Muninn credentials
 export const searchObjects = async (
-  jwt: string,
   page: number,
   pageSize: number,
   search?: string
@@ -30,27 +28,15 @@ export const searchObjects = async (
     };
   }
   searchQuery = searchQuery.replaceAll(' ', '&');
-  const response = await axios.get(
-    `${MUNINN_API}/objects?search=${searchQuery}&page=${page}&pageSize=${pageSize}`,
-    {
-      headers: {
-        Authorization: `Bearer ${jwt}`,
-        'Content-Type': 'application/json',
-      },
-    }
-  );
+  const response = await api.get('/muninn/objects', {
+    params: { search: searchQuery, page, pageSize },
+  });
   return response.data;
 };
 
 export const getObjectDetail = async (
-  jwt: string,
   object_id: string
 ): Promise<ObjectDetail> => {
-  const response = await axios.get(`${MUNINN_API}/objects/${object_id}`, {
-    headers: {
-      Authorization: `Bearer ${jwt}`,
-      'Content-Type': 'application/json',
-    },
-  });
+  const response = await api.get(`/muninn/objects/${object_id}`);
   return response.data;
 };
@@ -1,6 +1,4 @@
-import axios from 'axios';
-
-const API_BASE_URL = process.env.REACT_APP_API_URL;
+import { api } from './client';
 
 export const listObjects = async (params?: {
   id?: string;
@@ -13,6 +11,6 @@ export const listObjects = async (params?: {
       if (value) searchParams.append(key, value.toString());
     });
   }
-  const response = await axios.get(`${API_BASE_URL}/objects?${searchParams}`);
+  const response = await api.get(`/objects?${searchParams}`);
   return response.data;
 };
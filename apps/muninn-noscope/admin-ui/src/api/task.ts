@@ -1,7 +1,6 @@
-import axios from 'axios';
 import { Task } from '../types';
+import { api } from './client';
 
-const API_BASE_URL = process.env.REACT_APP_API_URL;
 interface TaskResponse {
   tasks: Task[];
   pagination: {
@@ -23,7 +22,7 @@ export const listTasks = async (params?: {
       if (value) searchParams.append(key, value.toString());
     });
   }
-  const response = await axios.get(`${API_BASE_URL}/tasks?${searchParams}`);
+  const response = await api.get(`/tasks?${searchParams}`);
   return response.data;
 };
 
@@ -31,6 +30,6 @@ export const createTask = async (data: {
   object_id: string;
   input: Record<string, any>;
 }): Promise<Task> => {
-  const response = await axios.post(`${API_BASE_URL}/tasks`, data);
+  const response = await api.post('/tasks', data);
   return response.data;
 };
@@ -1,39 +1,15 @@
 import { WorkerMetrics } from '../types';
+import { api } from './client';
 
-const API_BASE_URL = process.env.REACT_APP_API_URL;
-
-export const getMetrics = async (secret: string): Promise<WorkerMetrics> => {
-  const response = await fetch(API_BASE_URL + '/api/worker/metrics', {
-    headers: {
-      'x-control-key': secret || '',
-    },
-  });
-  if (!response.ok) {
-    throw new Error('Failed to fetch worker metrics');
-  }
-  return response.json();
+export const getMetrics = async (): Promise<WorkerMetrics> => {
+  const response = await api.get('/api/worker/metrics');
+  return response.data;
 };
 
-export const startWorker = async (secret: string): Promise<void> => {
-  const response = await fetch(API_BASE_URL + '/api/worker/start', {
-    method: 'POST',
-    headers: {
-      'x-control-key': secret || '',
-    },
-  });
-  if (!response.ok) {
-    throw new Error('Failed to start worker');
-  }
+export const startWorker = async (): Promise<void> => {
+  await api.post('/api/worker/start');
 };
 
-export const stopWorker = async (secret: string): Promise<void> => {
-  const response = await fetch(API_BASE_URL + '/api/worker/stop', {
-    method: 'POST',
-    headers: {
-      'x-control-key': secret || '',
-    },
-  });
-  if (!response.ok) {
-    throw new Error('Failed to stop worker');
-  }
+export const stopWorker = async (): Promise<void> => {
+  await api.post('/api/worker/stop');
 };
@@ -24,7 +24,6 @@ import {
 import { getMetrics, startWorker, stopWorker } from '../api/worker';
 import { WorkerMetrics } from '../types';
 import dayjs from 'dayjs';
-import { useSecret } from '../context/SecretContext';
 
 dayjs.extend(require('dayjs/plugin/relativeTime'));
 
@@ -37,10 +36,9 @@ export function WorkerManagement() {
   const [isStarting, setIsStarting] = useState(false);
   const [isStopping, setIsStopping] = useState(false);
   const toast = useToast();
-  const { secretKey } = useSecret();
   const fetchMetrics = useCallback(async () => {
     try {
-      const data = await getMetrics(secretKey);
+      const data = await getMetrics();
       setMetrics(data);
       setError(null);
     } catch (err) {
@@ -48,7 +46,7 @@ export function WorkerManagement() {
     } finally {
       setLoading(false);
     }
-  }, [secretKey]);
+  }, []);
 
   useEffect(() => {
     fetchMetrics();
@@ -59,7 +57,7 @@ export function WorkerManagement() {
   const handleStartWorker = async () => {
     setIsStarting(true);
     try {
-      await startWorker(secretKey);
+      await startWorker();
       toast({
         title: 'Worker started successfully',
         status: 'success',
@@ -81,7 +79,7 @@ export function WorkerManagement() {
   const handleStopWorker = async () => {
     setIsStopping(true);
     try {
-      await stopWorker(secretKey);
+      await stopWorker();
       toast({
         title: 'Worker stopped successfully',
         status: 'success',